@@ -0,0 +1,396 @@
+package controllers
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	storagehelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/storage"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// CreateAttachment 	godoc
+//
+//	@Summary		Upload attachment
+//	@Description	Upload a file to a ticket as multipart/form-data. The type of the file is detected from its content, only images, PDFs, plain text files (e.g. logs), zip and gzip archives are allowed. The response contains a signed download URL that expires after 15 minutes.
+//	@Security		ApiKeyAuth
+//	@Tags			Attachment
+//	@Router			/kanban/v1/tickets/{ticketId}/attachments [post]
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			file		formData	file	true	"the file to upload"
+//	@Success		201			{object}	models.AttachmentResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		413			{object}	models.ErrorMessage{}
+//	@Failure		415			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateAttachment(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	maxSize := models.AttachmentMaxSize()
+	tooLargeMessage := models.ErrorMessage{
+		Message: fmt.Sprintf("file must not be larger than %d MB", maxSize>>20),
+	}
+
+	// leave some room for the other parts of the multipart body
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, tooLargeMessage)
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "file is required",
+		})
+		return
+	}
+
+	if fileHeader.Size > maxSize {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, tooLargeMessage)
+		return
+	}
+
+	if fileHeader.Size == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "file is empty",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid file",
+		})
+		return
+	}
+	defer file.Close()
+
+	contentType, err := detectContentType(file)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid file",
+		})
+		return
+	}
+
+	if !models.IsAttachmentContentType(contentType) {
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, models.ErrorMessage{
+			Message: "file type " + contentType + " is not allowed",
+		})
+		return
+	}
+
+	newAttachment := models.Attachment{
+		ID:          helpers.GenerateUUIDWithoutHyphen(),
+		Filename:    models.SanitizeAttachmentFilename(fileHeader.Filename),
+		ContentType: contentType,
+		Size:        fileHeader.Size,
+		TicketID:    ticket.ID,
+		UserID:      user.ID,
+		User:        *user,
+	}
+	newAttachment.StorageKey = models.AttachmentStorageKey(ticket.ID, newAttachment.ID)
+
+	if err := d.Storage.Put(c.Request.Context(), newAttachment.StorageKey, file, newAttachment.Size, contentType); err != nil {
+		logrus.Error("Failed to store attachment:", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to store file",
+		})
+		return
+	}
+
+	if err := d.DB.Omit("Ticket", "User").Create(&newAttachment).Error; err != nil {
+		deleteStoredFiles(d.Storage, newAttachment.StorageKey)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create attachment",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachmentResponse(c, newAttachment))
+}
+
+// GetAttachmentList 	godoc
+//
+//	@Summary		Get the attachments of a ticket
+//	@Description	Get the attachments of a ticket, oldest first. Every attachment has a fresh signed download URL.
+//	@Security		ApiKeyAuth
+//	@Tags			Attachment
+//	@Router			/kanban/v1/tickets/{ticketId}/attachments [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Success		200			{object}	[]models.AttachmentResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func GetAttachmentList(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	var attachments []models.Attachment
+	if err := d.DB.Preload("User").Where("ticket_id = ?", ticket.ID).Order("created_at ASC").Find(&attachments).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get all attachments",
+		})
+		return
+	}
+
+	result := []models.AttachmentResponse{}
+	for _, attachment := range attachments {
+		result = append(result, attachmentResponse(c, attachment))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetAttachmentById 	godoc
+//
+//	@Summary		Get attachment by the attachment ID
+//	@Description	Get an attachment of a ticket with a fresh signed download URL
+//	@Security		ApiKeyAuth
+//	@Tags			Attachment
+//	@Router			/kanban/v1/tickets/{ticketId}/attachments/{attachmentId} [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId		path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			attachmentId	path		string	true	"Attachment ID"
+//	@Success		200				{object}	models.AttachmentResponse{}
+//	@Failure		401				{object}	models.ErrorMessage{}
+//	@Failure		404				{object}	models.ErrorMessage{}
+func GetAttachmentById(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+	attachmentId := c.Param("attachmentId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	attachment, err := findAttachment(d.DB, user, ticketId, attachmentId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "attachment not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, attachmentResponse(c, *attachment))
+}
+
+// DeleteAttachment 	godoc
+//
+//	@Summary		Delete attachment
+//	@Description	Delete an attachment of a ticket together with its file
+//	@Security		ApiKeyAuth
+//	@Tags			Attachment
+//	@Router			/kanban/v1/tickets/{ticketId}/attachments/{attachmentId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId		path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			attachmentId	path		string	true	"Attachment ID"
+//	@Success		200				{object}	models.AttachmentDeleteResponse{}
+//	@Failure		401				{object}	models.ErrorMessage{}
+//	@Failure		404				{object}	models.ErrorMessage{}
+//	@Failure		500				{object}	models.ErrorMessage{}
+func DeleteAttachment(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+	attachmentId := c.Param("attachmentId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	attachment, err := findAttachment(d.DB, user, ticketId, attachmentId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "attachment not found",
+		})
+		return
+	}
+
+	if err := d.DB.Omit("Ticket", "User").Delete(attachment).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to delete attachment",
+		})
+		return
+	}
+
+	deleteStoredFiles(d.Storage, attachment.StorageKey)
+
+	c.JSON(http.StatusOK, models.AttachmentDeleteResponse{
+		Message: "success",
+	})
+}
+
+// DownloadAttachment 	godoc
+//
+//	@Summary		Download attachment
+//	@Description	Download the file of an attachment. This endpoint needs no access token, the URL is signed instead. Get a signed URL from the download_url of an attachment. Attachments of tickets in the trash are not found.
+//	@Tags			Attachment
+//	@Router			/attachments/{attachmentId} [get]
+//	@Produce		octet-stream
+//	@Param			attachmentId	path		string	true	"Attachment ID"
+//	@Param			expires			query		int		true	"unix time the URL expires at"
+//	@Param			signature		query		string	true	"signature of the URL"
+//	@Success		200				{file}		binary
+//	@Failure		403				{object}	models.ErrorMessage{}
+//	@Failure		404				{object}	models.ErrorMessage{}
+//	@Failure		500				{object}	models.ErrorMessage{}
+func DownloadAttachment(d *models.DBInstance, c *gin.Context) {
+	attachmentId := c.Param("attachmentId")
+
+	if !storagehelper.VerifyDownload(attachmentId, c.Query("expires"), c.Query("signature"), time.Now()) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorMessage{
+			Message: "download URL is invalid or expired",
+		})
+		return
+	}
+
+	// attachments of tickets in the trash can't be downloaded, even with a signed URL
+	var attachment models.Attachment
+	err := d.DB.
+		Joins("JOIN tickets ON tickets.id = attachments.ticket_id AND tickets.deleted_at IS NULL").
+		Where("attachments.id = ?", attachmentId).
+		First(&attachment).Error
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "attachment not found",
+		})
+		return
+	}
+
+	file, err := d.Storage.Open(c.Request.Context(), attachment.StorageKey)
+	if errors.Is(err, storagehelper.ErrNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "file not found",
+		})
+		return
+	}
+	if err != nil {
+		logrus.Error("Failed to open attachment:", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to download file",
+		})
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, file, map[string]string{
+		"Content-Disposition": attachment.ContentDisposition(),
+		"Cache-Control":       "private, no-store",
+	})
+}
+
+// helpers
+func findAttachment(db *gorm.DB, user *models.User, ticketId string, attachmentId string) (*models.Attachment, error) {
+	ticket, err := findTicket(db, user, ticketId)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachment models.Attachment
+	if err := db.Preload("User").Where("ticket_id = ? AND id = ?", ticket.ID, attachmentId).First(&attachment).Error; err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// attachmentResponse adds a signed download URL to the attachment
+func attachmentResponse(c *gin.Context, attachment models.Attachment) models.AttachmentResponse {
+	expiresAt := time.Now().Add(models.ATTACHMENT_DOWNLOAD_URL_TTL).Truncate(time.Second)
+	query := url.Values{
+		"expires":   {strconv.FormatInt(expiresAt.Unix(), 10)},
+		"signature": {storagehelper.SignDownload(attachment.ID, expiresAt)},
+	}
+
+	response := attachment.ToAttachmentResponse()
+	response.DownloadURL = helpers.GetBaseUrl(c) + "/attachments/" + attachment.ID + "?" + query.Encode()
+	response.DownloadURLExpiresAt = expiresAt
+
+	return response
+}
+
+// detectContentType sniffs the type of the file from its first bytes and rewinds it
+func detectContentType(file io.ReadSeeker) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return http.DetectContentType(head[:n]), nil
+}
+
+/*
+files are deleted after the rows of their attachments, a file that can't
+be deleted is only left behind in the storage
+*/
+func deleteStoredFiles(storage storagehelper.Storage, keys ...string) {
+	for _, key := range keys {
+		if err := storage.Delete(stdcontext.Background(), key); err != nil {
+			logrus.Error("Failed to delete stored file:", err)
+		}
+	}
+}
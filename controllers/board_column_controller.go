@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetBoardColumns 	godoc
+//
+//	@Summary		Get the workflow of a board
+//	@Description	Get the ordered workflow columns of a board
+//	@Security		ApiKeyAuth
+//	@Tags			Board
+//	@Router			/kanban/v1/boards/{boardId}/columns [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			boardId	path		string	true	"Board ID"
+//	@Success		200		{object}	[]models.BoardColumnResponse{}
+//	@Failure		401		{object}	models.ErrorMessage{}
+//	@Failure		404		{object}	models.ErrorMessage{}
+//	@Failure		500		{object}	models.ErrorMessage{}
+func GetBoardColumns(d *models.DBInstance, c *gin.Context) {
+	boardId := c.Param("boardId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	board, err := findBoard(d.DB, user, boardId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "board not found",
+		})
+		return
+	}
+
+	columns, err := models.GetBoardColumns(d.DB, board.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to get board workflow",
+		})
+		return
+	}
+
+	result := []models.BoardColumnResponse{}
+	for _, column := range columns {
+		result = append(result, column.ToBoardColumnResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateBoardColumns 	godoc
+//
+//	@Summary		Update the workflow of a board
+//	@Description	Replace the workflow columns of a board. The order of the columns in the request is the order on the board. Columns that still have tickets cannot be removed. A column without wip_limit has no WIP limit, lowering the limit below the current number of tickets only blocks new tickets.
+//	@Security		ApiKeyAuth
+//	@Tags			Board
+//	@Router			/kanban/v1/boards/{boardId}/columns [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			boardId		path		string								true	"Board ID"
+//	@Param			requestBody	body		models.BoardColumnsUpdateRequest{}	true	"Request Body"
+//	@Success		200			{object}	[]models.BoardColumnResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func UpdateBoardColumns(d *models.DBInstance, c *gin.Context) {
+	boardId := c.Param("boardId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.BoardColumnsUpdateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	board, err := findBoard(d.DB, user, boardId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "board not found",
+		})
+		return
+	}
+
+	existingColumns, err := models.GetBoardColumns(d.DB, board.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to get board workflow",
+		})
+		return
+	}
+
+	requestedKeys := make(map[string]bool, len(reqBody.Columns))
+	for _, column := range reqBody.Columns {
+		requestedKeys[column.Key] = true
+	}
+
+	// columns that still have tickets cannot be removed
+	var removedColumns []models.BoardColumn
+	for _, column := range existingColumns {
+		if requestedKeys[column.Key] {
+			continue
+		}
+
+		var ticketCount int64
+		if err := d.DB.Model(&models.Ticket{}).Where("board_id = ? AND status = ?", board.ID, column.Key).Count(&ticketCount).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+				Message: "failed to update board workflow",
+			})
+			return
+		}
+
+		if ticketCount > 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+				Message: "column \"" + column.Key + "\" still has tickets and cannot be removed",
+			})
+			return
+		}
+
+		removedColumns = append(removedColumns, column)
+	}
+
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		for _, column := range removedColumns {
+			if err := tx.Delete(&column).Error; err != nil {
+				return err
+			}
+		}
+
+		for position, column := range reqBody.Columns {
+			err := tx.Where(models.BoardColumn{BoardID: board.ID, Key: column.Key}).
+				Assign(map[string]interface{}{
+					"name":      column.Name,
+					"color":     column.Color,
+					"position":  position,
+					"wip_limit": column.WIPLimit,
+				}).
+				FirstOrCreate(&models.BoardColumn{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to update board workflow",
+		})
+		return
+	}
+
+	columns, err := models.GetBoardColumns(d.DB, board.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to get board workflow",
+		})
+		return
+	}
+
+	result := []models.BoardColumnResponse{}
+	for _, column := range columns {
+		result = append(result, column.ToBoardColumnResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
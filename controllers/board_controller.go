@@ -0,0 +1,330 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateBoard 	godoc
+//
+//	@Summary		Create board
+//	@Description	Create a board. The key of the board is the prefix of the keys of its tickets, e.g. KAN in KAN-42. It is derived from the name when omitted and can't be changed later. Boards are created in the personal workspace of the user unless a workspace is given, which requires the admin role in that workspace.
+//	@Security		ApiKeyAuth
+//	@Tags			Board
+//	@Router			/kanban/v1/boards [post]
+//	@Router			/kanban/v1/workspaces/{workspaceId}/boards [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspaceId	path		string						false	"Workspace ID (the personal workspace is used when omitted)"
+//	@Param			requestBody	body		models.BoardCreateRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.BoardResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateBoard(d *models.DBInstance, c *gin.Context) {
+	workspaceId := c.Param("workspaceId")
+
+	var reqBody models.BoardCreateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var workspace *models.Workspace
+	if workspaceId == "" {
+		workspace, err = models.GetPersonalWorkspace(d.DB, user.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+				Message: "failed to get personal workspace",
+			})
+			return
+		}
+	} else {
+		workspace, _, err = findWorkspace(d.DB, user, workspaceId)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+				Message: "workspace not found",
+			})
+			return
+		}
+	}
+
+	newBoard := models.Board{
+		Name:        reqBody.Name,
+		Description: reqBody.Description,
+		Key:         reqBody.Key,
+		UserID:      user.ID,
+		WorkspaceID: workspace.ID,
+	}
+
+	// the workspace stays locked until the board is created, so boards created at the same time can't get the same key
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := models.LockWorkspace(tx, workspace.ID); err != nil {
+			return err
+		}
+
+		if reqBody.Key != "" {
+			taken, err := models.IsBoardKeyTaken(tx, workspace.ID, reqBody.Key)
+			if err != nil {
+				return err
+			}
+			if taken {
+				return errBoardKeyTaken
+			}
+		}
+
+		return tx.Create(&newBoard).Error
+	})
+	if errors.Is(err, errBoardKeyTaken) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "key \"" + reqBody.Key + "\" is already used by another board",
+		})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create board",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newBoard.ToBoardResponse())
+}
+
+// GetBoardList 	godoc
+//
+//	@Summary		Get a list of boards
+//	@Description	Get a list of boards of all workspaces the user stored in the token is a member of
+//	@Security		ApiKeyAuth
+//	@Tags			Board
+//	@Router			/kanban/v1/boards [get]
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	[]models.BoardResponse{}
+//	@Failure		400	{object}	models.ErrorMessage{}
+//	@Failure		401	{object}	models.ErrorMessage{}
+func GetBoardList(d *models.DBInstance, c *gin.Context) {
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var boards []models.Board
+	if err := d.DB.Scopes(models.BoardsVisibleTo(user)).Order("created_at ASC").Find(&boards).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get all boards",
+		})
+		return
+	}
+
+	result := []models.BoardResponse{}
+	for _, board := range boards {
+		result = append(result, board.ToBoardResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetBoardById 	godoc
+//
+//	@Summary		Get board by the board ID
+//	@Description	Get board by the board ID
+//	@Security		ApiKeyAuth
+//	@Tags			Board
+//	@Router			/kanban/v1/boards/{boardId} [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			boardId	path		string	true	"Board ID"
+//	@Success		200		{object}	models.BoardResponse{}
+//	@Failure		401		{object}	models.ErrorMessage{}
+//	@Failure		404		{object}	models.ErrorMessage{}
+func GetBoardById(d *models.DBInstance, c *gin.Context) {
+	boardId := c.Param("boardId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	board, err := findBoard(d.DB, user, boardId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "board not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, board.ToBoardResponse())
+}
+
+// UpdateBoard 	godoc
+//
+//	@Summary		Update a board
+//	@Description	Update the name and the description of a board
+//	@Security		ApiKeyAuth
+//	@Tags			Board
+//	@Router			/kanban/v1/boards/{boardId} [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			boardId		path		string						true	"Board ID"
+//	@Param			requestBody	body		models.BoardUpdateRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.BoardResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func UpdateBoard(d *models.DBInstance, c *gin.Context) {
+	boardId := c.Param("boardId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.BoardUpdateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	board, err := findBoard(d.DB, user, boardId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "board not found",
+		})
+		return
+	}
+
+	board.Name = reqBody.Name
+	board.Description = reqBody.Description
+
+	if err := d.DB.Save(board).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to update board",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, board.ToBoardResponse())
+}
+
+// DeleteBoard 	godoc
+//
+//	@Summary		Delete board
+//	@Description	Delete a board together with its tickets. The tickets of a deleted board can't be restored.
+//	@Security		ApiKeyAuth
+//	@Tags			Board
+//	@Router			/kanban/v1/boards/{boardId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			boardId	path		string	true	"Board ID"
+//	@Success		200		{object}	models.BoardDeleteResponse{}
+//	@Failure		401		{object}	models.ErrorMessage{}
+//	@Failure		404		{object}	models.ErrorMessage{}
+//	@Failure		500		{object}	models.ErrorMessage{}
+func DeleteBoard(d *models.DBInstance, c *gin.Context) {
+	boardId := c.Param("boardId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	board, err := findBoard(d.DB, user, boardId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "board not found",
+		})
+		return
+	}
+
+	/*
+		soft delete the board and its tickets. The tickets can't be restored
+		without their board, their history still shows who deleted them.
+	*/
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		var tickets []models.Ticket
+		if err := tx.Where("board_id = ?", board.ID).Find(&tickets).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("board_id = ?", board.ID).Delete(&models.Ticket{}).Error; err != nil {
+			return err
+		}
+
+		for i := range tickets {
+			if err := recordTicketEvents(tx, models.TICKET_EVENT_DELETED, user, nil, &tickets[i]); err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(board).Error
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to delete board",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BoardDeleteResponse{
+		Message: "success",
+	})
+}
+
+// helpers
+var errBoardKeyTaken = errors.New("board key is already taken")
+
+func findBoard(db *gorm.DB, user *models.User, boardId string) (*models.Board, error) {
+	var board models.Board
+	if err := db.Scopes(models.BoardsVisibleTo(user)).Where("boards.id = ?", boardId).First(&board).Error; err != nil {
+		return nil, err
+	}
+
+	return &board, nil
+}
@@ -0,0 +1,389 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// CreateChecklistItem 	godoc
+//
+//	@Summary		Create checklist item
+//	@Description	Add an item at the bottom of the checklist of a ticket
+//	@Security		ApiKeyAuth
+//	@Tags			Checklist
+//	@Router			/kanban/v1/tickets/{ticketId}/checklist [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string							true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			requestBody	body		models.ChecklistItemRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.ChecklistItemResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateChecklistItem(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	var reqBody models.ChecklistItemRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	if abortOnInvalidChecklistItem(d.DB, c, ticket, reqBody) {
+		return
+	}
+
+	newItem := models.ChecklistItem{
+		Text:     reqBody.Text,
+		Done:     reqBody.Done,
+		Assignee: reqBody.Assignee,
+		TicketID: ticket.ID,
+	}
+
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		// new items are placed at the bottom of the checklist
+		rank, err := getRankInChecklist(tx, &newItem, "", "")
+		if err != nil {
+			return err
+		}
+		newItem.Rank = rank
+
+		return tx.Omit("Ticket").Create(&newItem).Error
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create checklist item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newItem.ToChecklistItemResponse())
+
+	broadcastChecklistEvent(d.DB, user, "checklist.created", newItem)
+}
+
+// GetChecklist 	godoc
+//
+//	@Summary		Get the checklist of a ticket
+//	@Description	Get the items of the checklist of a ticket in their order
+//	@Security		ApiKeyAuth
+//	@Tags			Checklist
+//	@Router			/kanban/v1/tickets/{ticketId}/checklist [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Success		200			{object}	[]models.ChecklistItemResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func GetChecklist(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	var items []models.ChecklistItem
+	if err := d.DB.Where("ticket_id = ?", ticket.ID).Scopes(models.OrderChecklistItems).Find(&items).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get checklist",
+		})
+		return
+	}
+
+	result := []models.ChecklistItemResponse{}
+	for _, item := range items {
+		result = append(result, item.ToChecklistItemResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateChecklistItem 	godoc
+//
+//	@Summary		Update a checklist item
+//	@Description	Update the text, the done flag and the assignee of a checklist item
+//	@Security		ApiKeyAuth
+//	@Tags			Checklist
+//	@Router			/kanban/v1/tickets/{ticketId}/checklist/{itemId} [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string							true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			itemId		path		string							true	"Checklist item ID"
+//	@Param			requestBody	body		models.ChecklistItemRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.ChecklistItemResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func UpdateChecklistItem(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+	itemId := c.Param("itemId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.ChecklistItemRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	ticket, item, err := findChecklistItem(d.DB, user, ticketId, itemId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "checklist item not found",
+		})
+		return
+	}
+
+	if abortOnInvalidChecklistItem(d.DB, c, ticket, reqBody) {
+		return
+	}
+
+	item.Text = reqBody.Text
+	item.Done = reqBody.Done
+	item.Assignee = reqBody.Assignee
+
+	if err := d.DB.Omit("Ticket").Save(item).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to update checklist item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, item.ToChecklistItemResponse())
+
+	broadcastChecklistEvent(d.DB, user, "checklist.updated", *item)
+}
+
+// MoveChecklistItem 	godoc
+//
+//	@Summary		Move a checklist item
+//	@Description	Place a checklist item between two other items of the checklist. Without neighbours the item is placed at the bottom of the checklist.
+//	@Security		ApiKeyAuth
+//	@Tags			Checklist
+//	@Router			/kanban/v1/tickets/{ticketId}/checklist/{itemId}/move [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string								true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			itemId		path		string								true	"Checklist item ID"
+//	@Param			requestBody	body		models.ChecklistItemMoveRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.ChecklistItemResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func MoveChecklistItem(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+	itemId := c.Param("itemId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.ChecklistItemMoveRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	_, item, err := findChecklistItem(d.DB, user, ticketId, itemId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "checklist item not found",
+		})
+		return
+	}
+
+	item.Rank, err = getRankInChecklist(d.DB, item, reqBody.BeforeID, reqBody.AfterID)
+	if errors.Is(err, errInvalidChecklistNeighbours) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: err.Error(),
+		})
+		return
+	}
+	if err == nil {
+		err = d.DB.Model(item).UpdateColumn("rank", item.Rank).Error
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to move checklist item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, item.ToChecklistItemResponse())
+
+	broadcastChecklistEvent(d.DB, user, "checklist.moved", *item)
+}
+
+// DeleteChecklistItem 	godoc
+//
+//	@Summary		Delete checklist item
+//	@Description	Delete an item of the checklist of a ticket
+//	@Security		ApiKeyAuth
+//	@Tags			Checklist
+//	@Router			/kanban/v1/tickets/{ticketId}/checklist/{itemId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			itemId		path		string	true	"Checklist item ID"
+//	@Success		200			{object}	models.ChecklistItemDeleteResponse{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func DeleteChecklistItem(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+	itemId := c.Param("itemId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	_, item, err := findChecklistItem(d.DB, user, ticketId, itemId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "checklist item not found",
+		})
+		return
+	}
+
+	if err := d.DB.Omit("Ticket").Delete(item).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to delete checklist item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ChecklistItemDeleteResponse{
+		Message: "success",
+	})
+
+	broadcastChecklistEvent(d.DB, user, "checklist.deleted", *item)
+}
+
+// helpers
+func findChecklistItem(db *gorm.DB, user *models.User, ticketId string, itemId string) (*models.Ticket, *models.ChecklistItem, error) {
+	ticket, err := findTicket(db, user, ticketId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var item models.ChecklistItem
+	if err := db.Where("ticket_id = ? AND id = ?", ticket.ID, itemId).First(&item).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return ticket, &item, nil
+}
+
+// items can only be assigned to the teams the ticket can be assigned to
+func abortOnInvalidChecklistItem(db *gorm.DB, c *gin.Context, ticket *models.Ticket, reqBody models.ChecklistItemRequest) bool {
+	rules, err := getTicketRules(db, ticket.BoardID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to get ticket rules",
+		})
+		return true
+	}
+
+	if err := reqBody.Validate(rules.Teams); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return true
+	}
+
+	return false
+}
+
+var errInvalidChecklistNeighbours = errors.New("before_id and after_id must be items of the checklist in the right order")
+
+// getRankInChecklist returns a rank that places the item between the given neighbours of its checklist
+func getRankInChecklist(db *gorm.DB, item *models.ChecklistItem, beforeId string, afterId string) (string, error) {
+	checklist := func() *gorm.DB {
+		return db.Model(&models.ChecklistItem{}).Where("ticket_id = ? AND id <> ?", item.TicketID, item.ID)
+	}
+
+	return getRankBetween(checklist, beforeId, afterId, errInvalidChecklistNeighbours)
+}
+
+// checklist events carry the ticket of the item, so clients can update its progress
+func broadcastChecklistEvent(db *gorm.DB, user *models.User, event string, item models.ChecklistItem) {
+	ticket, err := findTicket(db, user, item.TicketID)
+	if err != nil {
+		logrus.Error("Failed to get the ticket of the checklist item:", err)
+		return
+	}
+
+	itemResponse := item.ToChecklistItemResponse()
+	ticketResponse := ticket.ToTicketResponse()
+	broadcastWSMessage(db, ticket.BoardID, models.WSMessage{
+		Event:         event,
+		ChecklistItem: &itemResponse,
+		Ticket:        &ticketResponse,
+	})
+}
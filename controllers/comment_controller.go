@@ -0,0 +1,340 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateComment 	godoc
+//
+//	@Summary		Create comment
+//	@Description	Comment on a ticket. Set parent_id to reply to a thread. Replies can't be replied to. Users are mentioned by their email or username, e.g. @jane@example.com or @jane_doe, and have to be able to see the ticket.
+//	@Security		ApiKeyAuth
+//	@Tags			Comment
+//	@Router			/kanban/v1/tickets/{ticketId}/comments [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string							true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			requestBody	body		models.CommentCreateRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.CommentResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateComment(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	var reqBody models.CommentCreateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	newComment := models.Comment{
+		Body:     reqBody.Body,
+		TicketID: ticket.ID,
+		UserID:   user.ID,
+	}
+
+	if reqBody.ParentID != "" {
+		// only threads can be replied to
+		var parent models.Comment
+		err := d.DB.Where("ticket_id = ? AND id = ? AND parent_id IS NULL", ticket.ID, reqBody.ParentID).First(&parent).Error
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+				Message: "parent_id must be a comment of the ticket that is not a reply",
+			})
+			return
+		}
+		newComment.ParentID = &parent.ID
+	}
+
+	mentionedUsers, err := findMentionedUsers(d.DB, ticket.BoardID, "", reqBody.Body)
+	if abortOnTicketError(c, err, "failed to create comment") {
+		return
+	}
+
+	var notifications []models.Notification
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newComment).Error; err != nil {
+			return err
+		}
+
+		notifications, err = createMentions(tx, user, ticket, &newComment.ID, mentionedUsers)
+		return err
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create comment",
+		})
+		return
+	}
+
+	newComment.User = *user
+
+	c.JSON(http.StatusCreated, newComment.ToCommentResponse())
+
+	broadcastCommentEvent(d.DB, ticket.BoardID, "comment.created", newComment.ToCommentResponse())
+	pushNotifications(notifications)
+}
+
+// GetCommentList 	godoc
+//
+//	@Summary		Get the comments of a ticket
+//	@Description	Get the threads of a ticket with their replies, oldest first
+//	@Security		ApiKeyAuth
+//	@Tags			Comment
+//	@Router			/kanban/v1/tickets/{ticketId}/comments [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Success		200			{object}	[]models.CommentResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func GetCommentList(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	var comments []models.Comment
+	err = d.DB.
+		Preload("User").
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("comments.created_at ASC")
+		}).
+		Preload("Replies.User").
+		Where("ticket_id = ? AND parent_id IS NULL", ticket.ID).
+		Order("created_at ASC").
+		Find(&comments).Error
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get all comments",
+		})
+		return
+	}
+
+	result := []models.CommentResponse{}
+	for _, comment := range comments {
+		result = append(result, comment.ToCommentResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateComment 	godoc
+//
+//	@Summary		Update a comment
+//	@Description	Edit the body of a comment. Only the author of a comment can edit it. Users mentioned for the first time have to be able to see the ticket.
+//	@Security		ApiKeyAuth
+//	@Tags			Comment
+//	@Router			/kanban/v1/tickets/{ticketId}/comments/{commentId} [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string							true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			commentId	path		string							true	"Comment ID"
+//	@Param			requestBody	body		models.CommentUpdateRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.CommentResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func UpdateComment(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+	commentId := c.Param("commentId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.CommentUpdateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	comment, err := findComment(d.DB, user, ticketId, commentId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "comment not found",
+		})
+		return
+	}
+
+	if comment.UserID != user.ID {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorMessage{
+			Message: "only the author can edit the comment",
+		})
+		return
+	}
+
+	// only the mentions that are new in the edited comment are recorded
+	mentionedUsers, err := findMentionedUsers(d.DB, comment.Ticket.BoardID, comment.Body, reqBody.Body)
+	if abortOnTicketError(c, err, "failed to update comment") {
+		return
+	}
+
+	editedAt := time.Now()
+	var notifications []models.Notification
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(comment).Updates(models.Comment{Body: reqBody.Body, EditedAt: &editedAt}).Error; err != nil {
+			return err
+		}
+
+		notifications, err = createMentions(tx, user, &comment.Ticket, &comment.ID, mentionedUsers)
+		return err
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to update comment",
+		})
+		return
+	}
+	comment.Body = reqBody.Body
+	comment.EditedAt = &editedAt
+
+	c.JSON(http.StatusOK, comment.ToCommentResponse())
+
+	broadcastCommentEvent(d.DB, comment.Ticket.BoardID, "comment.updated", comment.ToCommentResponse())
+	pushNotifications(notifications)
+}
+
+// DeleteComment 	godoc
+//
+//	@Summary		Delete comment
+//	@Description	Delete a comment. Deleting a thread also deletes its replies. Only the author of a comment can delete it.
+//	@Security		ApiKeyAuth
+//	@Tags			Comment
+//	@Router			/kanban/v1/tickets/{ticketId}/comments/{commentId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			commentId	path		string	true	"Comment ID"
+//	@Success		200			{object}	models.CommentDeleteResponse{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func DeleteComment(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+	commentId := c.Param("commentId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	comment, err := findComment(d.DB, user, ticketId, commentId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "comment not found",
+		})
+		return
+	}
+
+	if comment.UserID != user.ID {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorMessage{
+			Message: "only the author can delete the comment",
+		})
+		return
+	}
+
+	// soft delete the comment together with its replies
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("parent_id = ?", comment.ID).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(comment).Error
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to delete comment",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CommentDeleteResponse{
+		Message: "success",
+	})
+
+	broadcastCommentEvent(d.DB, comment.Ticket.BoardID, "comment.deleted", comment.ToCommentResponse())
+}
+
+// helpers
+func findComment(db *gorm.DB, user *models.User, ticketId string, commentId string) (*models.Comment, error) {
+	ticket, err := findTicket(db, user, ticketId)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment models.Comment
+	if err := db.Preload("User").Where("ticket_id = ? AND id = ?", ticket.ID, commentId).First(&comment).Error; err != nil {
+		return nil, err
+	}
+	comment.Ticket = *ticket
+
+	return &comment, nil
+}
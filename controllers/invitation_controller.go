@@ -0,0 +1,441 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateInvitation 	godoc
+//
+//	@Summary		Create invitation
+//	@Description	Invite an email address to join a workspace with a role. Requires the admin role in the workspace, only owners can invite owners. The token of the invitation is only part of this response, share it with the invitee. It can be used once and expires after 7 days. Invitees accept it with their account or create their account with it.
+//	@Security		ApiKeyAuth
+//	@Tags			Invitation
+//	@Router			/iam/v1/invitations [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			requestBody	body		models.InvitationCreateRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.InvitationResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateInvitation(d *models.DBInstance, c *gin.Context) {
+	var reqBody models.InvitationCreateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	workspace, role, err := findWorkspace(d.DB, user, reqBody.WorkspaceID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "workspace not found",
+		})
+		return
+	}
+
+	if abortOnInvitationRole(c, role, reqBody.Role) {
+		return
+	}
+
+	email := strings.ToLower(reqBody.Email)
+
+	var memberCount int64
+	err = d.DB.Model(&models.Membership{}).
+		Joins("JOIN users ON users.id = memberships.user_id").
+		Where("memberships.workspace_id = ? AND LOWER(users.email) = ?", workspace.ID, email).
+		Count(&memberCount).Error
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create invitation",
+		})
+		return
+	}
+
+	if memberCount > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: errAlreadyMember.Error(),
+		})
+		return
+	}
+
+	var pendingCount int64
+	if err := d.DB.Model(&models.Invitation{}).Scopes(models.InvitationsPending).Where("workspace_id = ? AND email = ?", workspace.ID, email).Count(&pendingCount).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create invitation",
+		})
+		return
+	}
+
+	if pendingCount > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "email already has a pending invitation to the workspace, resend it instead",
+		})
+		return
+	}
+
+	newInvitation := models.Invitation{
+		WorkspaceID: workspace.ID,
+		Email:       email,
+		Role:        reqBody.Role,
+		InvitedByID: user.ID,
+	}
+
+	token, err := newInvitation.RenewToken(time.Now())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create invitation",
+		})
+		return
+	}
+
+	if err := d.DB.Create(&newInvitation).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create invitation",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newInvitation.ToInvitationResponse(token))
+}
+
+// GetInvitationList 	godoc
+//
+//	@Summary		Get a list of pending invitations
+//	@Description	Get the invitations that haven't been accepted yet of the workspaces the user is an admin of, newest first. Expired invitations are listed as well, they can be resent.
+//	@Security		ApiKeyAuth
+//	@Tags			Invitation
+//	@Router			/iam/v1/invitations [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"only list the invitations of this workspace"
+//	@Success		200				{object}	[]models.InvitationResponse{}
+//	@Failure		400				{object}	models.ErrorMessage{}
+//	@Failure		401				{object}	models.ErrorMessage{}
+func GetInvitationList(d *models.DBInstance, c *gin.Context) {
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	dbQuery := d.DB.Scopes(models.InvitationsManagedBy(user), models.InvitationsPending)
+	if workspaceId := c.Query("workspace_id"); workspaceId != "" {
+		dbQuery = dbQuery.Where("invitations.workspace_id = ?", workspaceId)
+	}
+
+	var invitations []models.Invitation
+	if err := dbQuery.Order("invitations.created_at DESC").Find(&invitations).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get all invitations",
+		})
+		return
+	}
+
+	result := []models.InvitationResponse{}
+	for _, invitation := range invitations {
+		result = append(result, invitation.ToInvitationResponse(""))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ResendInvitation 	godoc
+//
+//	@Summary		Resend invitation
+//	@Description	Replace the token of a pending invitation and start a new expiry period. The previous token can't be used anymore, share the new one with the invitee.
+//	@Security		ApiKeyAuth
+//	@Tags			Invitation
+//	@Router			/iam/v1/invitations/{invitationId}/resend [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			invitationId	path		string	true	"Invitation ID"
+//	@Success		200				{object}	models.InvitationResponse{}
+//	@Failure		401				{object}	models.ErrorMessage{}
+//	@Failure		403				{object}	models.ErrorMessage{}
+//	@Failure		404				{object}	models.ErrorMessage{}
+//	@Failure		500				{object}	models.ErrorMessage{}
+func ResendInvitation(d *models.DBInstance, c *gin.Context) {
+	invitationId := c.Param("invitationId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	invitation, role, err := findInvitation(d.DB, user, invitationId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "invitation not found",
+		})
+		return
+	}
+
+	if abortOnInvitationRole(c, role, invitation.Role) {
+		return
+	}
+
+	token, err := invitation.RenewToken(time.Now())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to resend invitation",
+		})
+		return
+	}
+
+	if err := d.DB.Save(invitation).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to resend invitation",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation.ToInvitationResponse(token))
+}
+
+// RevokeInvitation 	godoc
+//
+//	@Summary		Revoke invitation
+//	@Description	Delete a pending invitation, its token can't be used anymore
+//	@Security		ApiKeyAuth
+//	@Tags			Invitation
+//	@Router			/iam/v1/invitations/{invitationId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			invitationId	path		string	true	"Invitation ID"
+//	@Success		200				{object}	models.InvitationDeleteResponse{}
+//	@Failure		401				{object}	models.ErrorMessage{}
+//	@Failure		403				{object}	models.ErrorMessage{}
+//	@Failure		404				{object}	models.ErrorMessage{}
+//	@Failure		500				{object}	models.ErrorMessage{}
+func RevokeInvitation(d *models.DBInstance, c *gin.Context) {
+	invitationId := c.Param("invitationId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	invitation, role, err := findInvitation(d.DB, user, invitationId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "invitation not found",
+		})
+		return
+	}
+
+	if abortOnInvitationRole(c, role, invitation.Role) {
+		return
+	}
+
+	if err := d.DB.Delete(invitation).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to revoke invitation",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.InvitationDeleteResponse{
+		Message: "success",
+	})
+}
+
+// AcceptInvitation 	godoc
+//
+//	@Summary		Accept invitation
+//	@Description	Join the workspace of an invitation with the role of the invitation. The invitation must have been sent to the email of the user stored in the token. Users without an account pass the token to Create user instead.
+//	@Security		ApiKeyAuth
+//	@Tags			Invitation
+//	@Router			/iam/v1/invitations/accept [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			requestBody	body		models.InvitationAcceptRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.WorkspaceResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func AcceptInvitation(d *models.DBInstance, c *gin.Context) {
+	var reqBody models.InvitationAcceptRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	invitation, err := findInvitationByToken(d.DB, reqBody.Token, user.Email)
+	if err == nil {
+		err = d.DB.Transaction(func(tx *gorm.DB) error {
+			return acceptInvitation(tx, invitation, user)
+		})
+	}
+	if abortOnInvitationError(c, err, "failed to accept invitation") {
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation.Workspace.ToWorkspaceResponse(invitation.Role))
+}
+
+// helpers
+var (
+	errInvitationNotFound = errors.New("invitation not found or expired")
+	errInvitationEmail    = errors.New("invitation was sent to another email")
+	errAlreadyMember      = errors.New("user is already a member of the workspace")
+)
+
+// findInvitation returns a pending invitation together with the role of the user in its workspace
+func findInvitation(db *gorm.DB, user *models.User, invitationId string) (*models.Invitation, string, error) {
+	var invitation models.Invitation
+	if err := db.Scopes(models.InvitationsManagedBy(user), models.InvitationsPending).Where("invitations.id = ?", invitationId).First(&invitation).Error; err != nil {
+		return nil, "", err
+	}
+
+	role, err := models.GetWorkspaceRole(db, user.ID, invitation.WorkspaceID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &invitation, role, nil
+}
+
+// findInvitationByToken returns the invitation of the token if it can be accepted by the email
+func findInvitationByToken(db *gorm.DB, token string, email string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := db.Scopes(models.InvitationsByToken(token)).Preload("Workspace").First(&invitation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(invitation.Email, email) {
+		return nil, errInvitationEmail
+	}
+
+	return &invitation, nil
+}
+
+/*
+acceptInvitation adds the user to the workspace of the invitation. The
+invitation is marked as accepted first, so a token can't be used twice
+by concurrent requests.
+*/
+func acceptInvitation(tx *gorm.DB, invitation *models.Invitation, user *models.User) error {
+	now := time.Now()
+	result := tx.Model(&models.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL", invitation.ID).
+		Update("accepted_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errInvitationNotFound
+	}
+	invitation.AcceptedAt = &now
+
+	if _, err := models.GetWorkspaceRole(tx, user.ID, invitation.WorkspaceID); err == nil {
+		return errAlreadyMember
+	}
+
+	return tx.Omit("Workspace", "User").Create(&models.Membership{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      user.ID,
+		Role:        invitation.Role,
+	}).Error
+}
+
+// owner invitations are managed by owners only
+func abortOnInvitationRole(c *gin.Context, role string, invitationRole string) bool {
+	if !models.HasWorkspaceRole(role, models.WORKSPACE_ROLE_ADMIN) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorMessage{
+			Message: "requires the " + models.WORKSPACE_ROLE_ADMIN + " role in the workspace",
+		})
+		return true
+	}
+
+	if invitationRole == models.WORKSPACE_ROLE_OWNER && role != models.WORKSPACE_ROLE_OWNER {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorMessage{
+			Message: errOwnerRoleForbidden.Error(),
+		})
+		return true
+	}
+
+	return false
+}
+
+// abortOnInvitationError writes the response for errors of accepting an invitation
+func abortOnInvitationError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, errInvitationNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: err.Error(),
+		})
+	case errors.Is(err, errInvitationEmail):
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorMessage{
+			Message: err.Error(),
+		})
+	case errors.Is(err, errAlreadyMember):
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: err.Error(),
+		})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: message,
+		})
+	}
+	return true
+}
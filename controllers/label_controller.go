@@ -0,0 +1,376 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateLabel 	godoc
+//
+//	@Summary		Create label
+//	@Description	Create a label that tickets of the board can be tagged with
+//	@Security		ApiKeyAuth
+//	@Tags			Label
+//	@Router			/kanban/v1/boards/{boardId}/labels [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			boardId		path		string					true	"Board ID"
+//	@Param			requestBody	body		models.LabelRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.LabelResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateLabel(d *models.DBInstance, c *gin.Context) {
+	boardId := c.Param("boardId")
+
+	var reqBody models.LabelRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	board, err := findBoard(d.DB, user, boardId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "board not found",
+		})
+		return
+	}
+
+	if isLabelNameUsed(d.DB, board.ID, reqBody.Name, "") {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "label name is already used",
+		})
+		return
+	}
+
+	newLabel := models.Label{
+		Name:    reqBody.Name,
+		Color:   reqBody.Color,
+		BoardID: board.ID,
+	}
+
+	if err := d.DB.Create(&newLabel).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create label",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newLabel.ToLabelResponse())
+}
+
+// GetLabelList 	godoc
+//
+//	@Summary		Get a list of labels
+//	@Description	Get the labels of a board
+//	@Security		ApiKeyAuth
+//	@Tags			Label
+//	@Router			/kanban/v1/boards/{boardId}/labels [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			boardId	path		string	true	"Board ID"
+//	@Success		200		{object}	[]models.LabelResponse{}
+//	@Failure		400		{object}	models.ErrorMessage{}
+//	@Failure		401		{object}	models.ErrorMessage{}
+//	@Failure		404		{object}	models.ErrorMessage{}
+func GetLabelList(d *models.DBInstance, c *gin.Context) {
+	boardId := c.Param("boardId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	board, err := findBoard(d.DB, user, boardId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "board not found",
+		})
+		return
+	}
+
+	var labels []models.Label
+	if err := d.DB.Where("board_id = ?", board.ID).Scopes(models.OrderLabels).Find(&labels).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get all labels",
+		})
+		return
+	}
+
+	result := []models.LabelResponse{}
+	for _, label := range labels {
+		result = append(result, label.ToLabelResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateLabel 	godoc
+//
+//	@Summary		Update a label
+//	@Description	Update the name and the color of a label
+//	@Security		ApiKeyAuth
+//	@Tags			Label
+//	@Router			/kanban/v1/labels/{labelId} [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			labelId		path		string					true	"Label ID"
+//	@Param			requestBody	body		models.LabelRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.LabelResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func UpdateLabel(d *models.DBInstance, c *gin.Context) {
+	labelId := c.Param("labelId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.LabelRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	label, err := findLabel(d.DB, user, labelId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "label not found",
+		})
+		return
+	}
+
+	if isLabelNameUsed(d.DB, label.BoardID, reqBody.Name, label.ID) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "label name is already used",
+		})
+		return
+	}
+
+	label.Name = reqBody.Name
+	label.Color = reqBody.Color
+
+	if err := d.DB.Save(label).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to update label",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, label.ToLabelResponse())
+}
+
+// DeleteLabel 	godoc
+//
+//	@Summary		Delete label
+//	@Description	Delete a label and remove it from all tickets
+//	@Security		ApiKeyAuth
+//	@Tags			Label
+//	@Router			/kanban/v1/labels/{labelId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			labelId	path		string	true	"Label ID"
+//	@Success		200		{object}	models.LabelDeleteResponse{}
+//	@Failure		401		{object}	models.ErrorMessage{}
+//	@Failure		404		{object}	models.ErrorMessage{}
+//	@Failure		500		{object}	models.ErrorMessage{}
+func DeleteLabel(d *models.DBInstance, c *gin.Context) {
+	labelId := c.Param("labelId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	label, err := findLabel(d.DB, user, labelId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "label not found",
+		})
+		return
+	}
+
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM ticket_labels WHERE label_id = ?", label.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(label).Error
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to delete label",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.LabelDeleteResponse{
+		Message: "success",
+	})
+}
+
+// AttachTicketLabel 	godoc
+//
+//	@Summary		Attach a label to a ticket
+//	@Description	Tag a ticket with a label of its board. Attaching a label twice has no effect.
+//	@Security		ApiKeyAuth
+//	@Tags			Label
+//	@Router			/kanban/v1/tickets/{ticketId}/labels/{labelId} [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			labelId		path		string	true	"Label ID"
+//	@Param			If-Match	header		string	false	"ETag of the ticket the change is based on"
+//	@Success		200			{object}	models.TicketResponse{}
+//	@Header			200			{string}	ETag	"version of the ticket"
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		412			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func AttachTicketLabel(d *models.DBInstance, c *gin.Context) {
+	changeTicketLabel(d, c, func(tx *gorm.DB, ticket *models.Ticket, label *models.Label) error {
+		for _, attached := range ticket.Labels {
+			if attached.ID == label.ID {
+				return nil
+			}
+		}
+
+		// the label itself already exists
+		return tx.Model(ticket).Omit("Labels.*").Association("Labels").Append(label)
+	})
+}
+
+// DetachTicketLabel 	godoc
+//
+//	@Summary		Detach a label from a ticket
+//	@Description	Remove a label from a ticket. Detaching a label that isn't attached has no effect.
+//	@Security		ApiKeyAuth
+//	@Tags			Label
+//	@Router			/kanban/v1/tickets/{ticketId}/labels/{labelId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			labelId		path		string	true	"Label ID"
+//	@Param			If-Match	header		string	false	"ETag of the ticket the change is based on"
+//	@Success		200			{object}	models.TicketResponse{}
+//	@Header			200			{string}	ETag	"version of the ticket"
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		412			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func DetachTicketLabel(d *models.DBInstance, c *gin.Context) {
+	changeTicketLabel(d, c, func(tx *gorm.DB, ticket *models.Ticket, label *models.Label) error {
+		return tx.Model(ticket).Association("Labels").Delete(label)
+	})
+}
+
+// helpers
+func findLabel(db *gorm.DB, user *models.User, labelId string) (*models.Label, error) {
+	var label models.Label
+	if err := db.Scopes(models.LabelsVisibleTo(user)).Where("labels.id = ?", labelId).First(&label).Error; err != nil {
+		return nil, err
+	}
+
+	return &label, nil
+}
+
+func isLabelNameUsed(db *gorm.DB, boardId string, name string, exceptLabelId string) bool {
+	var count int64
+	db.Model(&models.Label{}).Where("board_id = ? AND name = ? AND id <> ?", boardId, name, exceptLabelId).Count(&count)
+	return count > 0
+}
+
+func changeTicketLabel(d *models.DBInstance, c *gin.Context, change func(tx *gorm.DB, ticket *models.Ticket, label *models.Label) error) {
+	ticketId := c.Param("ticketId")
+	labelId := c.Param("labelId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	label, err := findLabel(d.DB, user, labelId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "label not found",
+		})
+		return
+	}
+
+	if label.BoardID != ticket.BoardID {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "label must belong to the board of the ticket",
+		})
+		return
+	}
+
+	err = updateTicket(d.DB, user, ticket, c.GetHeader("If-Match"), models.TICKET_EVENT_UPDATED, false, func(tx *gorm.DB) error {
+		return change(tx, ticket, label)
+	})
+	if abortOnTicketError(c, err, "failed to update ticket labels") {
+		return
+	}
+
+	c.Header("ETag", ticket.ETag())
+	c.JSON(http.StatusOK, ticket.ToTicketResponse())
+
+	broadcastTicketEvent(d.DB, "updated", ticket.ToTicketResponse())
+}
@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetNotificationList 	godoc
+//
+//	@Summary		Get notifications
+//	@Description	Get a page of the notifications of the user stored in the token, newest first, together with the number of unread notifications. Only notifications about tickets the user can still see are returned. Use next_cursor as the cursor query param to get the next page. New notifications are also pushed to the websocket connections of the user.
+//	@Security		ApiKeyAuth
+//	@Tags			Notification
+//	@Router			/iam/v1/notifications [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			unread	query		bool	false	"only unread notifications"
+//	@Param			limit	query		int		false	"page size"	minimum(1)	maximum(100)	default(50)
+//	@Param			cursor	query		string	false	"next_cursor of the previous page"
+//	@Success		200		{object}	models.NotificationListResponse{}
+//	@Failure		400		{object}	models.ErrorMessage{}
+//	@Failure		401		{object}	models.ErrorMessage{}
+func GetNotificationList(d *models.DBInstance, c *gin.Context) {
+	var query models.NotificationListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid query params",
+		})
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+	query = query.WithDefaults()
+
+	var cursor *models.Cursor
+	if query.Cursor != "" {
+		// already validated
+		cursor, _ = models.DecodeCursor(query.Cursor)
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	dbQuery := d.DB.Scopes(models.NotificationsOf(user))
+	if query.Unread {
+		dbQuery = dbQuery.Scopes(models.NotificationsUnread)
+	}
+
+	var notifications []models.Notification
+	err = dbQuery.
+		Preload("Actor").
+		Preload("Ticket", func(db *gorm.DB) *gorm.DB {
+			// tickets in the trash are still shown
+			return db.Unscoped()
+		}).
+		Scopes(query.Page(cursor)).
+		Find(&notifications).Error
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get notifications",
+		})
+		return
+	}
+
+	result := models.NotificationListResponse{
+		Data: []models.NotificationResponse{},
+	}
+
+	if err := d.DB.Model(&models.Notification{}).Scopes(models.NotificationsOf(user), models.NotificationsUnread).Count(&result.UnreadCount).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to count unread notifications",
+		})
+		return
+	}
+
+	// one more notification than the limit means there is a next page
+	if len(notifications) > query.Limit {
+		notifications = notifications[:query.Limit]
+		lastNotification := notifications[len(notifications)-1]
+		result.NextCursor = models.Cursor{
+			Value: lastNotification.CreatedAt.Format(time.RFC3339Nano),
+			ID:    lastNotification.ID,
+		}.Encode()
+	}
+
+	for _, notification := range notifications {
+		result.Data = append(result.Data, notification.ToNotificationResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// MarkNotificationRead 	godoc
+//
+//	@Summary		Mark notification as read
+//	@Description	Mark a notification of the user stored in the token as read. Marking a read notification again has no effect.
+//	@Security		ApiKeyAuth
+//	@Tags			Notification
+//	@Router			/iam/v1/notifications/{notificationId}/read [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			notificationId	path		string	true	"Notification ID"
+//	@Success		200				{object}	models.NotificationResponse{}
+//	@Failure		401				{object}	models.ErrorMessage{}
+//	@Failure		404				{object}	models.ErrorMessage{}
+//	@Failure		500				{object}	models.ErrorMessage{}
+func MarkNotificationRead(d *models.DBInstance, c *gin.Context) {
+	notificationId := c.Param("notificationId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var notification models.Notification
+	err = d.DB.
+		Scopes(models.NotificationsOf(user)).
+		Preload("Actor").
+		Preload("Ticket", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Where("notifications.id = ?", notificationId).
+		First(&notification).Error
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "notification not found",
+		})
+		return
+	}
+
+	if err := d.DB.Model(&notification).Update("read", true).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to mark notification as read",
+		})
+		return
+	}
+	notification.Read = true
+
+	c.JSON(http.StatusOK, notification.ToNotificationResponse())
+}
+
+// MarkAllNotificationsRead 	godoc
+//
+//	@Summary		Mark all notifications as read
+//	@Description	Mark all unread notifications of the user stored in the token as read
+//	@Security		ApiKeyAuth
+//	@Tags			Notification
+//	@Router			/iam/v1/notifications/read-all [post]
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.NotificationReadAllResponse{}
+//	@Failure		401	{object}	models.ErrorMessage{}
+//	@Failure		500	{object}	models.ErrorMessage{}
+func MarkAllNotificationsRead(d *models.DBInstance, c *gin.Context) {
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	// notifications about tickets the user can't see anymore are left as they are
+	result := d.DB.Model(&models.Notification{}).Scopes(models.NotificationsOf(user), models.NotificationsUnread).Update("read", true)
+	if result.Error != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to mark notifications as read",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NotificationReadAllResponse{
+		Message: "success",
+		Updated: result.RowsAffected,
+	})
+}
+
+// helpers
+
+/*
+notifyUsers creates a notification of the same type for each user. Users
+aren't notified of what they did themselves. The ticket and the actor are
+set on the notifications, so they can be pushed right away.
+*/
+func notifyUsers(db *gorm.DB, actor *models.User, ticket *models.Ticket, notificationType string, commentId *string, userIds []string) ([]models.Notification, error) {
+	notifications := []models.Notification{}
+	notified := []string{}
+	for _, userId := range userIds {
+		if userId == actor.ID || slices.Contains(notified, userId) {
+			continue
+		}
+		notified = append(notified, userId)
+
+		notifications = append(notifications, models.Notification{
+			Type:      notificationType,
+			UserID:    userId,
+			ActorID:   actor.ID,
+			Actor:     *actor,
+			TicketID:  ticket.ID,
+			Ticket:    *ticket,
+			CommentID: commentId,
+		})
+	}
+
+	if len(notifications) == 0 {
+		return notifications, nil
+	}
+
+	// the actor and the ticket themselves already exist
+	if err := db.Omit(clause.Associations).Create(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+/*
+notifyTicketChanges notifies users that were assigned to or unassigned from
+the ticket. When the status changed, the assignees and the creator of the
+ticket are notified. before is nil for a created ticket.
+*/
+func notifyTicketChanges(db *gorm.DB, actor *models.User, before *models.Ticket, after *models.Ticket) ([]models.Notification, error) {
+	var beforeAssigneeIds []string
+	if before != nil {
+		beforeAssigneeIds = before.AssigneeIDs()
+	}
+	afterAssigneeIds := after.AssigneeIDs()
+
+	assigned := []string{}
+	for _, userId := range afterAssigneeIds {
+		if !slices.Contains(beforeAssigneeIds, userId) {
+			assigned = append(assigned, userId)
+		}
+	}
+
+	unassigned := []string{}
+	for _, userId := range beforeAssigneeIds {
+		if !slices.Contains(afterAssigneeIds, userId) {
+			unassigned = append(unassigned, userId)
+		}
+	}
+
+	statusChanged := []string{}
+	if before != nil && before.Status != after.Status {
+		// a new slice, so the assignee IDs are never changed
+		statusChanged = append(append(statusChanged, afterAssigneeIds...), after.UserID)
+	}
+
+	notifications := []models.Notification{}
+	for _, change := range []struct {
+		notificationType string
+		userIds          []string
+	}{
+		{models.NOTIFICATION_TYPE_ASSIGNED, assigned},
+		{models.NOTIFICATION_TYPE_UNASSIGNED, unassigned},
+		{models.NOTIFICATION_TYPE_STATUS_CHANGED, statusChanged},
+	} {
+		changeNotifications, err := notifyUsers(db, actor, after, change.notificationType, nil, change.userIds)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, changeNotifications...)
+	}
+
+	return notifications, nil
+}
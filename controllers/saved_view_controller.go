@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateSavedView 	godoc
+//
+//	@Summary		Create saved view
+//	@Description	Save a query of the ticket query language under a name. Pass the query as the q query param of the ticket list to apply the view.
+//	@Security		ApiKeyAuth
+//	@Tags			View
+//	@Router			/kanban/v1/views [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			requestBody	body		models.SavedViewRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.SavedViewResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateSavedView(d *models.DBInstance, c *gin.Context) {
+	var reqBody models.SavedViewRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	if isSavedViewNameUsed(d.DB, user, reqBody.Name, "") {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "view name is already used",
+		})
+		return
+	}
+
+	newView := models.SavedView{
+		Name:   reqBody.Name,
+		Query:  reqBody.Query,
+		UserID: user.ID,
+	}
+
+	if err := d.DB.Create(&newView).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create view",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newView.ToSavedViewResponse())
+}
+
+// GetSavedViewList 	godoc
+//
+//	@Summary		Get a list of saved views
+//	@Description	Get the saved views of the user stored in the token, ordered by name
+//	@Security		ApiKeyAuth
+//	@Tags			View
+//	@Router			/kanban/v1/views [get]
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	[]models.SavedViewResponse{}
+//	@Failure		400	{object}	models.ErrorMessage{}
+//	@Failure		401	{object}	models.ErrorMessage{}
+func GetSavedViewList(d *models.DBInstance, c *gin.Context) {
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var views []models.SavedView
+	if err := d.DB.Scopes(models.SavedViewsVisibleTo(user)).Order("saved_views.name ASC").Find(&views).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get all views",
+		})
+		return
+	}
+
+	result := []models.SavedViewResponse{}
+	for _, view := range views {
+		result = append(result, view.ToSavedViewResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetSavedViewById 	godoc
+//
+//	@Summary		Get saved view by the view ID
+//	@Description	Get saved view by the view ID
+//	@Security		ApiKeyAuth
+//	@Tags			View
+//	@Router			/kanban/v1/views/{viewId} [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			viewId	path		string	true	"View ID"
+//	@Success		200		{object}	models.SavedViewResponse{}
+//	@Failure		401		{object}	models.ErrorMessage{}
+//	@Failure		404		{object}	models.ErrorMessage{}
+func GetSavedViewById(d *models.DBInstance, c *gin.Context) {
+	viewId := c.Param("viewId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	view, err := findSavedView(d.DB, user, viewId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "view not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, view.ToSavedViewResponse())
+}
+
+// UpdateSavedView 	godoc
+//
+//	@Summary		Update a saved view
+//	@Description	Update the name and the query of a saved view
+//	@Security		ApiKeyAuth
+//	@Tags			View
+//	@Router			/kanban/v1/views/{viewId} [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			viewId		path		string						true	"View ID"
+//	@Param			requestBody	body		models.SavedViewRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.SavedViewResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func UpdateSavedView(d *models.DBInstance, c *gin.Context) {
+	viewId := c.Param("viewId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.SavedViewRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	view, err := findSavedView(d.DB, user, viewId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "view not found",
+		})
+		return
+	}
+
+	if isSavedViewNameUsed(d.DB, user, reqBody.Name, view.ID) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "view name is already used",
+		})
+		return
+	}
+
+	view.Name = reqBody.Name
+	view.Query = reqBody.Query
+
+	if err := d.DB.Save(view).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to update view",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, view.ToSavedViewResponse())
+}
+
+// DeleteSavedView 	godoc
+//
+//	@Summary		Delete saved view
+//	@Description	Delete a saved view
+//	@Security		ApiKeyAuth
+//	@Tags			View
+//	@Router			/kanban/v1/views/{viewId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			viewId	path		string	true	"View ID"
+//	@Success		200		{object}	models.SavedViewDeleteResponse{}
+//	@Failure		401		{object}	models.ErrorMessage{}
+//	@Failure		404		{object}	models.ErrorMessage{}
+//	@Failure		500		{object}	models.ErrorMessage{}
+func DeleteSavedView(d *models.DBInstance, c *gin.Context) {
+	viewId := c.Param("viewId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	view, err := findSavedView(d.DB, user, viewId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "view not found",
+		})
+		return
+	}
+
+	if err := d.DB.Delete(view).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to delete view",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SavedViewDeleteResponse{
+		Message: "success",
+	})
+}
+
+// helpers
+func findSavedView(db *gorm.DB, user *models.User, viewId string) (*models.SavedView, error) {
+	var view models.SavedView
+	if err := db.Scopes(models.SavedViewsVisibleTo(user)).Where("saved_views.id = ?", viewId).First(&view).Error; err != nil {
+		return nil, err
+	}
+
+	return &view, nil
+}
+
+func isSavedViewNameUsed(db *gorm.DB, user *models.User, name string, exceptViewId string) bool {
+	var count int64
+	db.Model(&models.SavedView{}).Scopes(models.SavedViewsVisibleTo(user)).Where("saved_views.name = ? AND saved_views.id <> ?", name, exceptViewId).Count(&count)
+	return count > 0
+}
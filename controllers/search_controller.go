@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+)
+
+// SearchTickets 	godoc
+//
+//	@Summary		Search tickets
+//	@Description	Full-text search over the title, the description and the comments of the tickets on the boards of the user stored in the token, best matches first. Words are matched by their stem, "quoted phrases", OR and -excluded words are supported. Matches in the highlights are wrapped in <mark></mark>, the rest of the highlights is HTML-escaped.
+//	@Security		ApiKeyAuth
+//	@Tags			Search
+//	@Router			/kanban/v1/search [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			q			query		string	true	"search terms"
+//	@Param			board_id	query		string	false	"only search the tickets of this board"
+//	@Param			limit		query		int		false	"max number of results"	minimum(1)	maximum(100)	default(20)
+//	@Success		200			{object}	models.SearchResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func SearchTickets(d *models.DBInstance, c *gin.Context) {
+	var query models.SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid query params",
+		})
+		return
+	}
+
+	query.Q = strings.TrimSpace(query.Q)
+	if err := query.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+	query = query.WithDefaults()
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	dbQuery := d.DB.Model(&models.Ticket{}).Scopes(models.TicketsVisibleTo(user))
+	if query.BoardID != "" {
+		if _, err := findBoard(d.DB, user, query.BoardID); err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+				Message: "board not found",
+			})
+			return
+		}
+		dbQuery = dbQuery.Where("tickets.board_id = ?", query.BoardID)
+	}
+
+	var hits []models.TicketSearchHit
+	if err := dbQuery.Scopes(models.TicketSearch(query.Q)).Limit(query.Limit).Scan(&hits).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to search tickets",
+		})
+		return
+	}
+
+	ticketIds := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ticketIds = append(ticketIds, hit.TicketID)
+	}
+
+	var tickets []models.Ticket
+	if err := d.DB.Scopes(models.TicketsWithDetails).Where("id IN ?", ticketIds).Find(&tickets).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to search tickets",
+		})
+		return
+	}
+
+	ticketsById := make(map[string]models.Ticket, len(tickets))
+	for _, ticket := range tickets {
+		ticketsById[ticket.ID] = ticket
+	}
+
+	result := models.SearchResponse{
+		Data: []models.SearchResult{},
+	}
+
+	// keep the order of the hits
+	for _, hit := range hits {
+		ticket, ok := ticketsById[hit.TicketID]
+		if !ok {
+			continue
+		}
+
+		result.Data = append(result.Data, models.SearchResult{
+			Ticket: ticket.ToTicketResponse(),
+			Score:  hit.Score,
+			Highlights: models.SearchHighlights{
+				Title:       hit.Title,
+				Description: hit.Description,
+				Comment:     hit.Comment,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
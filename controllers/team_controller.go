@@ -0,0 +1,310 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateTeam 	godoc
+//
+//	@Summary		Create team
+//	@Description	Create a team that tickets of the workspace can be assigned to. Teams are created in the personal workspace of the user unless a workspace is given, which requires the admin role in that workspace.
+//	@Security		ApiKeyAuth
+//	@Tags			Team
+//	@Router			/kanban/v1/teams [post]
+//	@Router			/kanban/v1/workspaces/{workspaceId}/teams [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspaceId	path		string						false	"Workspace ID (the personal workspace is used when omitted)"
+//	@Param			requestBody	body		models.TeamCreateRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.TeamResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateTeam(d *models.DBInstance, c *gin.Context) {
+	workspaceId := c.Param("workspaceId")
+
+	var reqBody models.TeamCreateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	workspace, err := findTeamWorkspace(d.DB, user, workspaceId)
+	if abortOnTeamWorkspaceError(c, err) {
+		return
+	}
+
+	var team models.Team
+	d.DB.Where("teams.workspace_id = ? AND teams.key = ?", workspace.ID, reqBody.Key).First(&team)
+	if team.ID != "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "team key is already used",
+		})
+		return
+	}
+
+	newTeam := models.Team{
+		Key:         reqBody.Key,
+		Name:        reqBody.Name,
+		WorkspaceID: workspace.ID,
+		UserID:      user.ID,
+	}
+
+	if err := d.DB.Create(&newTeam).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create team",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newTeam.ToTeamResponse())
+}
+
+// GetTeamList 	godoc
+//
+//	@Summary		Get a list of teams
+//	@Description	Get a list of teams the tickets of a workspace can be assigned to. The teams of the personal workspace of the user are listed unless a workspace is given.
+//	@Security		ApiKeyAuth
+//	@Tags			Team
+//	@Router			/kanban/v1/teams [get]
+//	@Router			/kanban/v1/workspaces/{workspaceId}/teams [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspaceId	path		string	false	"Workspace ID (the personal workspace is used when omitted)"
+//	@Success		200			{object}	[]models.TeamResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func GetTeamList(d *models.DBInstance, c *gin.Context) {
+	workspaceId := c.Param("workspaceId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	workspace, err := findTeamWorkspace(d.DB, user, workspaceId)
+	if abortOnTeamWorkspaceError(c, err) {
+		return
+	}
+
+	var teams []models.Team
+	if err := d.DB.Where("teams.workspace_id = ?", workspace.ID).Order("created_at ASC").Find(&teams).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get all teams",
+		})
+		return
+	}
+
+	result := []models.TeamResponse{}
+	for _, team := range teams {
+		result = append(result, team.ToTeamResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateTeam 	godoc
+//
+//	@Summary		Update a team
+//	@Description	Update the name of a team. The key of a team cannot be changed. Requires the admin role in the workspace of the team.
+//	@Security		ApiKeyAuth
+//	@Tags			Team
+//	@Router			/kanban/v1/teams/{teamId} [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			teamId		path		string						true	"Team ID"
+//	@Param			requestBody	body		models.TeamUpdateRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.TeamResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func UpdateTeam(d *models.DBInstance, c *gin.Context) {
+	teamId := c.Param("teamId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.TeamUpdateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	team, err := findTeam(d.DB, user, teamId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "team not found",
+		})
+		return
+	}
+
+	team.Name = reqBody.Name
+
+	if err := d.DB.Save(team).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to update team",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, team.ToTeamResponse())
+}
+
+// DeleteTeam 	godoc
+//
+//	@Summary		Delete team
+//	@Description	Delete a team. Teams that are still assigned to tickets of their workspace cannot be deleted. Requires the admin role in the workspace of the team.
+//	@Security		ApiKeyAuth
+//	@Tags			Team
+//	@Router			/kanban/v1/teams/{teamId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			teamId	path		string	true	"Team ID"
+//	@Success		200		{object}	models.TeamDeleteResponse{}
+//	@Failure		400		{object}	models.ErrorMessage{}
+//	@Failure		401		{object}	models.ErrorMessage{}
+//	@Failure		403		{object}	models.ErrorMessage{}
+//	@Failure		404		{object}	models.ErrorMessage{}
+//	@Failure		500		{object}	models.ErrorMessage{}
+func DeleteTeam(d *models.DBInstance, c *gin.Context) {
+	teamId := c.Param("teamId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	team, err := findTeam(d.DB, user, teamId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "team not found",
+		})
+		return
+	}
+
+	teamKeyJson, err := json.Marshal([]string{team.Key})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to delete team",
+		})
+		return
+	}
+
+	var ticketCount int64
+	err = d.DB.Model(&models.Ticket{}).
+		Joins("JOIN boards ON boards.id = tickets.board_id").
+		Where("boards.workspace_id = ? AND tickets.assignees @> ?::jsonb", team.WorkspaceID, string(teamKeyJson)).
+		Count(&ticketCount).Error
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to delete team",
+		})
+		return
+	}
+
+	if ticketCount > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "team \"" + team.Key + "\" is still assigned to tickets and cannot be deleted",
+		})
+		return
+	}
+
+	if err := d.DB.Delete(team).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to delete team",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TeamDeleteResponse{
+		Message: "success",
+	})
+}
+
+// helpers
+// teams are managed in the personal workspace of the user unless a workspace is given
+func findTeamWorkspace(db *gorm.DB, user *models.User, workspaceId string) (*models.Workspace, error) {
+	if workspaceId == "" {
+		return models.GetPersonalWorkspace(db, user.ID)
+	}
+
+	workspace, _, err := findWorkspace(db, user, workspaceId)
+	return workspace, err
+}
+
+func abortOnTeamWorkspaceError(c *gin.Context, err error) bool {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "workspace not found",
+		})
+		return true
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to get workspace",
+		})
+		return true
+	}
+
+	return false
+}
+
+func findTeam(db *gorm.DB, user *models.User, teamId string) (*models.Team, error) {
+	var team models.Team
+	if err := db.Scopes(models.TeamsVisibleTo(user)).Where("teams.id = ?", teamId).First(&team).Error; err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
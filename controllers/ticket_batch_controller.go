@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BatchTickets 	godoc
+//
+//	@Summary		Run ticket operations in bulk
+//	@Description	Create, update, move and delete tickets in a single DB transaction. Every operation gets a result with the status it would have had as a single request. By default failed operations are skipped and the other operations are applied. With all_or_nothing, nothing is applied when one operation fails. override_wip_limit applies to all operations. Connected members get a single batch event with the events of the applied operations in their workspaces.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket
+//	@Router			/kanban/v1/tickets:batch [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			requestBody			body		models.TicketBatchRequest{}	true	"Request Body"
+//	@Param			override_wip_limit	query		bool						false	"exceed the WIP limits of the columns (workspace admins only)"
+//	@Success		200					{object}	models.TicketBatchResponse{}
+//	@Failure		400					{object}	models.ErrorMessage{}
+//	@Failure		401					{object}	models.ErrorMessage{}
+//	@Failure		403					{object}	models.TicketBatchResponse{}
+//	@Failure		404					{object}	models.TicketBatchResponse{}
+//	@Failure		409					{object}	models.TicketBatchResponse{}
+//	@Failure		412					{object}	models.TicketBatchResponse{}
+//	@Failure		500					{object}	models.ErrorMessage{}
+func BatchTickets(d *models.DBInstance, c *gin.Context) {
+	/*
+		gin can't register "/tickets:batch" as a static path, so the route
+		is registered as "/tickets:action" and any other action is unknown.
+		"/tickets/batch" is an alias without an action.
+	*/
+	if action := c.Param("action"); action != "" && action != ":batch" {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "not found",
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.TicketBatchRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	overrideWIPLimit := isWIPLimitOverridden(c)
+
+	result := models.TicketBatchResponse{
+		Results: make([]models.TicketBatchResult, len(reqBody.Operations)),
+	}
+	var events []models.WSMessage
+	var batchNotifications []models.Notification
+	failedIndex := -1
+
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		for i, operation := range reqBody.Operations {
+			// every operation runs in its own savepoint, so a failed operation leaves nothing behind
+			var event models.WSMessage
+			var notifications []models.Notification
+			err := tx.Transaction(func(opTx *gorm.DB) error {
+				var err error
+				event, notifications, err = runTicketBatchOperation(opTx, user, operation, overrideWIPLimit)
+				return err
+			})
+			if err != nil {
+				status, body := ticketErrorResponse(err, fmt.Sprintf("failed to %s ticket", operation.Op))
+				result.Results[i] = models.TicketBatchResult{
+					Op:     operation.Op,
+					Status: status,
+					Error:  body,
+				}
+
+				if reqBody.AllOrNothing {
+					failedIndex = i
+					return errBatchFailed
+				}
+				continue
+			}
+
+			status := http.StatusOK
+			if operation.Op == models.TICKET_BATCH_CREATE {
+				status = http.StatusCreated
+			}
+
+			result.Results[i] = models.TicketBatchResult{
+				Op:     operation.Op,
+				Status: status,
+				Ticket: event.Ticket,
+			}
+			events = append(events, event)
+			batchNotifications = append(batchNotifications, notifications...)
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errBatchFailed) {
+		// the other operations have been rolled back or haven't run at all
+		for i, operation := range reqBody.Operations {
+			if i == failedIndex {
+				continue
+			}
+
+			result.Results[i] = models.TicketBatchResult{
+				Op:     operation.Op,
+				Status: http.StatusFailedDependency,
+				Error: models.ErrorMessage{
+					Message: fmt.Sprintf("not applied because operation %d failed", failedIndex),
+				},
+			}
+		}
+
+		c.AbortWithStatusJSON(result.Results[failedIndex].Status, result)
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to run batch",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+
+	if len(events) > 0 {
+		broadcastBatchEvents(d.DB, events)
+	}
+	pushNotifications(batchNotifications)
+}
+
+// helpers
+var errBatchFailed = errors.New("batch failed")
+
+// runTicketBatchOperation applies a single operation and returns the event and the notifications it causes
+func runTicketBatchOperation(db *gorm.DB, user *models.User, operation models.TicketBatchOperation, overrideWIPLimit bool) (models.WSMessage, []models.Notification, error) {
+	if operation.Op == models.TICKET_BATCH_CREATE {
+		board, err := findBatchBoard(db, user, operation.BoardID)
+		if err != nil {
+			return models.WSMessage{}, nil, err
+		}
+
+		if err := checkBatchWorkspaceRole(db, user, board.WorkspaceID); err != nil {
+			return models.WSMessage{}, nil, err
+		}
+
+		ticket, err := createTicket(db, user, board, *operation.Ticket, overrideWIPLimit)
+		if err != nil {
+			return models.WSMessage{}, nil, err
+		}
+
+		return newTicketWSMessage("created", ticket), ticket.Notifications, nil
+	}
+
+	ticket, err := findTicket(db, user, operation.TicketID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.WSMessage{}, nil, errTicketNotFound
+	}
+	if err != nil {
+		return models.WSMessage{}, nil, err
+	}
+
+	var board models.Board
+	if err := db.Where("id = ?", ticket.BoardID).First(&board).Error; err != nil {
+		return models.WSMessage{}, nil, err
+	}
+
+	if err := checkBatchWorkspaceRole(db, user, board.WorkspaceID); err != nil {
+		return models.WSMessage{}, nil, err
+	}
+
+	switch operation.Op {
+	case models.TICKET_BATCH_UPDATE:
+		err = patchTicket(db, user, ticket, operation.IfMatch, operation.Patch, overrideWIPLimit)
+		return newTicketWSMessage("updated", ticket), ticket.Notifications, err
+	case models.TICKET_BATCH_MOVE:
+		err = moveTicket(db, user, ticket, operation.IfMatch, *operation.Move, overrideWIPLimit)
+		return newTicketWSMessage("moved", ticket), ticket.Notifications, err
+	default:
+		err = deleteTicket(db, user, ticket, operation.IfMatch)
+		return newTicketWSMessage("deleted", ticket), nil, err
+	}
+}
+
+/*
+the single ticket routes check the role of the user in the workspace
+before they run, the operations of a batch are checked one by one
+*/
+var errBatchRoleForbidden = errors.New("requires the " + models.WORKSPACE_ROLE_MEMBER + " role in the workspace")
+
+func checkBatchWorkspaceRole(db *gorm.DB, user *models.User, workspaceId string) error {
+	role, err := models.GetWorkspaceRole(db, user.ID, workspaceId)
+	if err != nil {
+		return err
+	}
+
+	if !models.HasWorkspaceRole(role, models.WORKSPACE_ROLE_MEMBER) {
+		return errBatchRoleForbidden
+	}
+
+	return nil
+}
+
+// tickets are created on the default board when no board is given
+func findBatchBoard(db *gorm.DB, user *models.User, boardId string) (*models.Board, error) {
+	if boardId == "" {
+		return models.GetDefaultBoard(db, user.ID)
+	}
+
+	board, err := findBoard(db, user, boardId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errBoardNotFound
+	}
+
+	return board, err
+}
+
+func newTicketWSMessage(event string, ticket *models.Ticket) models.WSMessage {
+	ticketResponse := ticket.ToTicketResponse()
+	return models.WSMessage{
+		Event:  event,
+		Ticket: &ticketResponse,
+	}
+}
@@ -1,29 +1,48 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	patchhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/patch"
+	rankhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/rank"
 	"github.com/Manuel-Leleuly/kanban-flow-go/models"
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CreateTicket 	godoc
 //
 //	@Summary		Create ticket
-//	@Description	Create a ticket
+//	@Description	Create a ticket. assignee_ids assigns the ticket to members of the workspace of the board. Users mentioned in the description by their email or username, e.g. @jane@example.com or @jane_doe, have to be able to see the ticket. With template_id, the fields left out are filled in from a template of the board and the checklist of the template is added to the ticket. A ticket can't be created in a column that has reached its WIP limit unless an admin of the workspace sets override_wip_limit.
 //	@Security		ApiKeyAuth
 //	@Tags			Ticket
 //	@Router			/kanban/v1/tickets [post]
+//	@Router			/kanban/v1/boards/{boardId}/tickets [post]
 //	@Accept			json
 //	@Produce		json
-//	@Param			requestBody	body		models.TicketCreateRequest{}	true	"Request Body"
-//	@Success		201			{object}	models.TicketResponse{}
-//	@Failure		400			{object}	models.ErrorMessage{}
-//	@Failure		500			{object}	models.ErrorMessage{}
+//	@Param			boardId				path		string							false	"Board ID (the default board is used when omitted)"
+//	@Param			requestBody			body		models.TicketCreateRequest{}	true	"Request Body"
+//	@Param			override_wip_limit	query		bool							false	"exceed the WIP limit of the column (workspace admins only)"
+//	@Success		201					{object}	models.TicketResponse{}
+//	@Header			201					{string}	ETag	"version of the ticket"
+//	@Failure		400					{object}	models.ErrorMessage{}
+//	@Failure		403					{object}	models.ErrorMessage{}
+//	@Failure		404					{object}	models.ErrorMessage{}
+//	@Failure		409					{object}	models.WIPLimitErrorMessage{}
+//	@Failure		500					{object}	models.ErrorMessage{}
 func CreateTicket(d *models.DBInstance, c *gin.Context) {
+	boardId := c.Param("boardId")
+
 	var reqBody models.TicketCreateRequest
 	if err := c.Bind(&reqBody); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
@@ -32,13 +51,6 @@ func CreateTicket(d *models.DBInstance, c *gin.Context) {
 		return
 	}
 
-	if err := reqBody.Validate(); err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
-			Message: strings.Split(err.Error(), "; "),
-		})
-		return
-	}
-
 	user, err := context.GetUserFromContext(c)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
@@ -47,56 +59,99 @@ func CreateTicket(d *models.DBInstance, c *gin.Context) {
 		return
 	}
 
-	newTicket := models.Ticket{
-		Title:       reqBody.Title,
-		Description: reqBody.Description,
-		Assignees:   reqBody.Assignees,
-		Status:      reqBody.Status,
-		User:        *user,
+	var board *models.Board
+	if boardId == "" {
+		board, err = models.GetDefaultBoard(d.DB, user.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+				Message: "failed to get default board",
+			})
+			return
+		}
+	} else {
+		board, err = findBoard(d.DB, user, boardId)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+				Message: "board not found",
+			})
+			return
+		}
 	}
 
-	if err := d.DB.Create(&newTicket).Error; err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
-			Message: "failed to create ticket",
-		})
+	newTicket, err := createTicket(d.DB, user, board, reqBody, isWIPLimitOverridden(c))
+	if abortOnTicketError(c, err, "failed to create ticket") {
 		return
 	}
 
+	c.Header("ETag", newTicket.ETag())
 	c.JSON(http.StatusCreated, newTicket.ToTicketResponse())
 
-	websocketMessage := models.WSMessage{
-		Event:  "created",
-		Ticket: newTicket.ToTicketResponse(),
-	}
-	msg, err := websocketMessage.ToJsonMarshal()
-	if err != nil {
-		logrus.Error("Failed to marshal websocket message:", err)
-	} else {
-		BroadcastMessage(msg)
-	}
+	broadcastTicketEvent(d.DB, "created", newTicket.ToTicketResponse())
+	pushNotifications(newTicket.Notifications)
 }
 
 // GetTicketList 	godoc
 //
 //	@Summary		Get a list of tickets
-//	@Description	Get a list of tickets created by the user stored in the token
+//	@Description	Get a page of tickets on the boards of the user stored in the token. Use next_cursor as the cursor query param to get the next page.
 //	@Security		ApiKeyAuth
 //	@Tags			Ticket
 //	@Router			/kanban/v1/tickets [get]
+//	@Router			/kanban/v1/boards/{boardId}/tickets [get]
 //	@Accept			json
 //	@Produce		json
-//	@Param			title	query		string	false	"search by ticket title"
-//	@Success		200		{object}	[]models.TicketResponse{}
-//	@Failure		400		{object}	models.ErrorMessage{}
-//	@Failure		401		{object}	models.ErrorMessage{}
-//	@Failure		404		{object}	models.ErrorMessage{}
+//	@Param			boardId			path		string		false	"Board ID (tickets of all boards are returned when omitted)"
+//	@Param			q				query		string		false	"query of the ticket query language, for example: status:doing assignee:backend -label:wontfix updated>-7d"
+//	@Param			title			query		string		false	"search by ticket title"
+//	@Param			status			query		[]string	false	"filter by status"													collectionFormat(multi)
+//	@Param			assignee		query		[]string	false	"filter by assignee team, me for the tickets assigned to the user"	collectionFormat(multi)
+//	@Param			label			query		[]string	false	"filter by label name"												collectionFormat(multi)
+//	@Param			priority		query		[]string	false	"filter by priority"												collectionFormat(multi)
+//	@Param			min_priority	query		string		false	"only tickets with this priority or higher"	Enums(low, medium, high, urgent)
+//	@Param			overdue			query		bool		false	"only tickets past their due date that are not in the last column yet"
+//	@Param			due_after		query		string		false	"only tickets due at or after this time (RFC 3339)"
+//	@Param			due_before		query		string		false	"only tickets due before this time (RFC 3339)"
+//	@Param			created_after	query		string		false	"only tickets created at or after this time (RFC 3339)"
+//	@Param			created_before	query		string		false	"only tickets created before this time (RFC 3339)"
+//	@Param			updated_after	query		string		false	"only tickets updated at or after this time (RFC 3339)"
+//	@Param			updated_before	query		string		false	"only tickets updated before this time (RFC 3339)"
+//	@Param			sort_by			query		string		false	"sort field"	Enums(rank, created_at, updated_at, title)	default(rank)
+//	@Param			order			query		string		false	"sort order"	Enums(asc, desc)							default(asc)
+//	@Param			limit			query		int			false	"page size"		minimum(1)									maximum(100)	default(50)
+//	@Param			cursor			query		string		false	"next_cursor of the previous page"
+//	@Success		200				{object}	models.TicketListResponse{}
+//	@Failure		400				{object}	models.ErrorMessage{}
+//	@Failure		401				{object}	models.ErrorMessage{}
+//	@Failure		404				{object}	models.ErrorMessage{}
 func GetTicketList(d *models.DBInstance, c *gin.Context) {
 	/*
-		only returns tickets that belong to the user registered
-		in the token
+		only returns tickets on the boards that belong to the
+		user registered in the token
 	*/
 
-	title := c.Query("title")
+	boardId := c.Param("boardId")
+
+	var query models.TicketListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid query params",
+		})
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+	query = query.WithDefaults()
+
+	var cursor *models.Cursor
+	if query.Cursor != "" {
+		// already validated
+		cursor, _ = models.DecodeCursor(query.Cursor)
+	}
 
 	user, err := context.GetUserFromContext(c)
 	if err != nil {
@@ -106,29 +161,52 @@ func GetTicketList(d *models.DBInstance, c *gin.Context) {
 		return
 	}
 
-	dbQuery := d.DB.Where("user_id = ?", user.ID)
-	if len(title) > 0 {
-		dbQuery = dbQuery.Where("LOWER(title) like LOWER(?)", "%"+title+"%")
+	dbQuery := d.DB.Scopes(models.TicketsVisibleTo(user))
+	if boardId != "" {
+		if _, err := findBoard(d.DB, user, boardId); err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+				Message: "board not found",
+			})
+			return
+		}
+		dbQuery = dbQuery.Where("tickets.board_id = ?", boardId)
 	}
 
 	var tickets []models.Ticket
-	if err := dbQuery.Find(&tickets).Error; err != nil {
+	if err := dbQuery.Scopes(query.Filters(user), query.Page(cursor), models.TicketsWithDetails).Find(&tickets).Error; err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
 			Message: "failed to get all tickets",
 		})
 		return
 	}
 
-	if len(tickets) == 0 {
-		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
-			Message: "no tickets found",
-		})
-		return
+	result := models.TicketListResponse{
+		Data: []models.TicketResponse{},
+	}
+
+	// one more ticket than the limit means there is a next page
+	if len(tickets) > query.Limit {
+		tickets = tickets[:query.Limit]
+		lastTicket := tickets[len(tickets)-1]
+		result.NextCursor = models.Cursor{
+			Value: lastTicket.SortValue(query.SortBy),
+			ID:    lastTicket.ID,
+		}.Encode()
 	}
 
-	var result []models.TicketResponse
 	for _, ticket := range tickets {
-		result = append(result, ticket.ToTicketResponse())
+		result.Data = append(result.Data, ticket.ToTicketResponse())
+	}
+
+	if boardId != "" {
+		columns, err := getColumnWIP(d.DB, boardId)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+				Message: "failed to get WIP limits",
+			})
+			return
+		}
+		result.Columns = columns
 	}
 
 	c.JSON(http.StatusOK, result)
@@ -137,14 +215,16 @@ func GetTicketList(d *models.DBInstance, c *gin.Context) {
 // GetTicketById 	godoc
 //
 //	@Summary		Get ticket by the ticket ID
-//	@Description	Get ticket by the ticket ID
+//	@Description	Get ticket by the ticket ID together with the tickets linked to it
 //	@Security		ApiKeyAuth
 //	@Tags			Ticket
 //	@Router			/kanban/v1/tickets/{ticketId} [get]
 //	@Accept			json
 //	@Produce		json
-//	@Param			ticketId	path		string	true	"Ticket ID"
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
 //	@Success		200			{object}	models.TicketResponse{}
+//	@Header			200			{string}	ETag	"version of the ticket"
+//	@Failure		400			{object}	models.ErrorMessage{}
 //	@Failure		401			{object}	models.ErrorMessage{}
 //	@Failure		404			{object}	models.ErrorMessage{}
 func GetTicketById(d *models.DBInstance, c *gin.Context) {
@@ -158,32 +238,51 @@ func GetTicketById(d *models.DBInstance, c *gin.Context) {
 		return
 	}
 
-	var ticket models.Ticket
-	result := d.DB.Where("user_id = ? AND Tickets.id = ?", user.ID, ticketId).First(&ticket)
-	if result.Error != nil || ticket.ID == "" {
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
 			Message: "ticket not found",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, ticket.ToTicketResponse())
+	links, err := getTicketLinks(d.DB, ticket.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get ticket links",
+		})
+		return
+	}
+
+	response := ticket.ToTicketResponse()
+	response.Links = &links
+
+	c.Header("ETag", ticket.ETag())
+	c.JSON(http.StatusOK, response)
 }
 
 // UpdateTicket 	godoc
 //
 //	@Summary		Update a ticket
-//	@Description	Update a ticket
+//	@Description	Replace all editable fields of a ticket. Users mentioned for the first time in the description have to be able to see the ticket. Send the ETag of the ticket as If-Match to make sure nobody else changed the ticket in the meantime. A blocked ticket can't leave the first column of its board until all its blockers are done. A ticket can't be moved to a column that has reached its WIP limit unless an admin of the workspace sets override_wip_limit.
 //	@Security		ApiKeyAuth
 //	@Tags			Ticket
 //	@Router			/kanban/v1/tickets/{ticketId} [put]
 //	@Accept			json
 //	@Produce		json
-//	@Param			ticketId	path		string							true	"Ticket ID"
-//	@Param			requestBody	body		models.TicketUpdateRequest{}	true	"Request Body"
-//	@Success		200			{object}	models.TicketResponse{}
-//	@Failure		401			{object}	models.ErrorMessage{}
-//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Param			ticketId			path		string							true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			If-Match			header		string							false	"ETag of the ticket the change is based on"
+//	@Param			requestBody			body		models.TicketUpdateRequest{}	true	"Request Body"
+//	@Param			override_wip_limit	query		bool							false	"exceed the WIP limit of the column (workspace admins only)"
+//	@Success		200					{object}	models.TicketResponse{}
+//	@Header			200					{string}	ETag	"version of the ticket"
+//	@Failure		400					{object}	models.ErrorMessage{}
+//	@Failure		401					{object}	models.ErrorMessage{}
+//	@Failure		403					{object}	models.ErrorMessage{}
+//	@Failure		404					{object}	models.ErrorMessage{}
+//	@Failure		409					{object}	models.TicketBlockedErrorMessage{}
+//	@Failure		412					{object}	models.ErrorMessage{}
+//	@Failure		500					{object}	models.ErrorMessage{}
 func UpdateTicket(d *models.DBInstance, c *gin.Context) {
 	ticketId := c.Param("ticketId")
 
@@ -203,47 +302,164 @@ func UpdateTicket(d *models.DBInstance, c *gin.Context) {
 		return
 	}
 
-	if err := reqBody.Validate(); err != nil {
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	rules, err := getTicketRules(d.DB, ticket.BoardID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to get ticket rules",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(rules); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
 			Message: strings.Split(err.Error(), "; "),
 		})
 		return
 	}
 
-	var ticket models.Ticket
+	err = updateTicket(d.DB, user, ticket, c.GetHeader("If-Match"), models.TICKET_EVENT_UPDATED, isWIPLimitOverridden(c), func(tx *gorm.DB) error {
+		return applyTicketUpdate(tx, ticket, reqBody)
+	})
+	if abortOnTicketError(c, err, "failed to update ticket") {
+		return
+	}
+
+	c.Header("ETag", ticket.ETag())
+	c.JSON(http.StatusOK, ticket.ToTicketResponse())
+
+	broadcastTicketEvent(d.DB, "updated", ticket.ToTicketResponse())
+	pushNotifications(ticket.Notifications)
+}
 
-	result := d.DB.Where("user_id = ? AND Tickets.id = ?", user.ID, ticketId).First(&ticket)
-	if result.Error != nil || ticket.ID == "" {
-		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
-			Message: "ticket not found",
+// PatchTicket 	godoc
+//
+//	@Summary		Partially update a ticket
+//	@Description	Update a ticket with a JSON Merge Patch (RFC 7396). Fields that are left out stay untouched and fields set to null are cleared. Users mentioned for the first time in the description have to be able to see the ticket. Send the ETag of the ticket as If-Match to make sure nobody else changed the ticket in the meantime. A blocked ticket can't leave the first column of its board until all its blockers are done. A ticket can't be moved to a column that has reached its WIP limit unless an admin of the workspace sets override_wip_limit.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket
+//	@Router			/kanban/v1/tickets/{ticketId} [patch]
+//	@Accept			json
+//	@Accept			application/merge-patch+json
+//	@Produce		json
+//	@Param			ticketId			path		string							true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			If-Match			header		string							false	"ETag of the ticket the change is based on"
+//	@Param			requestBody			body		models.TicketUpdateRequest{}	true	"Merge patch of the ticket"
+//	@Param			override_wip_limit	query		bool							false	"exceed the WIP limit of the column (workspace admins only)"
+//	@Success		200					{object}	models.TicketResponse{}
+//	@Header			200					{string}	ETag	"version of the ticket"
+//	@Failure		400					{object}	models.ErrorMessage{}
+//	@Failure		401					{object}	models.ErrorMessage{}
+//	@Failure		403					{object}	models.ErrorMessage{}
+//	@Failure		404					{object}	models.ErrorMessage{}
+//	@Failure		409					{object}	models.TicketBlockedErrorMessage{}
+//	@Failure		412					{object}	models.ErrorMessage{}
+//	@Failure		500					{object}	models.ErrorMessage{}
+func PatchTicket(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
 		})
 		return
 	}
 
-	ticket.Title = reqBody.Title
-	ticket.Description = reqBody.Description
-	ticket.Assignees = reqBody.Assignees
-	ticket.Status = reqBody.Status
+	patch, err := c.GetRawData()
+	if err != nil || !json.Valid(patch) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
 
-	if err := d.DB.Save(&ticket).Error; err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
-			Message: "failed to update ticket",
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
 		})
 		return
 	}
 
+	err = patchTicket(d.DB, user, ticket, c.GetHeader("If-Match"), patch, isWIPLimitOverridden(c))
+	if abortOnTicketError(c, err, "failed to update ticket") {
+		return
+	}
+
+	c.Header("ETag", ticket.ETag())
 	c.JSON(http.StatusOK, ticket.ToTicketResponse())
 
-	websocketMessage := models.WSMessage{
-		Event:  "updated",
-		Ticket: ticket.ToTicketResponse(),
+	broadcastTicketEvent(d.DB, "updated", ticket.ToTicketResponse())
+	pushNotifications(ticket.Notifications)
+}
+
+// MoveTicket 	godoc
+//
+//	@Summary		Move a ticket
+//	@Description	Move a ticket to a column and place it between two neighbours of that column. Without neighbours the ticket is placed at the bottom of the column. A blocked ticket can't leave the first column of its board until all its blockers are done. A ticket can't be moved to a column that has reached its WIP limit unless an admin of the workspace sets override_wip_limit.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket
+//	@Router			/kanban/v1/tickets/{ticketId}/move [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId			path		string						true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			If-Match			header		string						false	"ETag of the ticket the change is based on"
+//	@Param			requestBody			body		models.TicketMoveRequest{}	true	"Request Body"
+//	@Param			override_wip_limit	query		bool						false	"exceed the WIP limit of the column (workspace admins only)"
+//	@Success		200					{object}	models.TicketResponse{}
+//	@Header			200					{string}	ETag	"version of the ticket"
+//	@Failure		400					{object}	models.ErrorMessage{}
+//	@Failure		401					{object}	models.ErrorMessage{}
+//	@Failure		403					{object}	models.ErrorMessage{}
+//	@Failure		404					{object}	models.ErrorMessage{}
+//	@Failure		409					{object}	models.TicketBlockedErrorMessage{}
+//	@Failure		412					{object}	models.ErrorMessage{}
+//	@Failure		500					{object}	models.ErrorMessage{}
+func MoveTicket(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
 	}
-	msg, err := websocketMessage.ToJsonMarshal()
+
+	var reqBody models.TicketMoveRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
 	if err != nil {
-		logrus.Error("Failed to marshal websocket message:", err)
-	} else {
-		BroadcastMessage(msg)
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	err = moveTicket(d.DB, user, ticket, c.GetHeader("If-Match"), reqBody, isWIPLimitOverridden(c))
+	if abortOnTicketError(c, err, "failed to move ticket") {
+		return
 	}
+
+	c.Header("ETag", ticket.ETag())
+	c.JSON(http.StatusOK, ticket.ToTicketResponse())
+
+	broadcastTicketEvent(d.DB, "moved", ticket.ToTicketResponse())
+	pushNotifications(ticket.Notifications)
 }
 
 // DeleteTicket 	godoc
@@ -255,10 +471,12 @@ func UpdateTicket(d *models.DBInstance, c *gin.Context) {
 //	@Router			/kanban/v1/tickets/{ticketId} [delete]
 //	@Accept			json
 //	@Produce		json
-//	@Param			ticketId	path		string	true	"Ticket ID"
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			If-Match	header		string	false	"ETag of the ticket the deletion is based on"
 //	@Success		200			{object}	models.TicketDeleteResponse{}
 //	@Failure		401			{object}	models.ErrorMessage{}
 //	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		412			{object}	models.ErrorMessage{}
 func DeleteTicket(d *models.DBInstance, c *gin.Context) {
 	ticketId := c.Param("ticketId")
 
@@ -270,36 +488,959 @@ func DeleteTicket(d *models.DBInstance, c *gin.Context) {
 		return
 	}
 
-	var ticket models.Ticket
-
-	result := d.DB.Where("user_id = ? AND Tickets.id = ?", user.ID, ticketId).First(&ticket)
-	if result.Error != nil || ticket.ID == "" {
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
 			Message: "ticket not found",
 		})
 		return
 	}
 
-	// soft delete ticket
-	if err := d.DB.Where("user_id = ? AND Tickets.id = ?", user.ID, ticketId).Delete(&ticket).Error; err != nil {
+	err = deleteTicket(d.DB, user, ticket, c.GetHeader("If-Match"))
+	if abortOnTicketError(c, err, "failed to delete ticket") {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TicketDeleteResponse{
+		Message: "success",
+	})
+
+	broadcastTicketEvent(d.DB, "deleted", ticket.ToTicketResponse())
+}
+
+// GetTrashedTicketList 	godoc
+//
+//	@Summary		Get the trash
+//	@Description	Get the deleted tickets on the boards of the user stored in the token, most recently deleted first. Deleted tickets can be restored or purged.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket
+//	@Router			/kanban/v1/tickets/trash [get]
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	[]models.TicketResponse{}
+//	@Failure		400	{object}	models.ErrorMessage{}
+//	@Failure		401	{object}	models.ErrorMessage{}
+func GetTrashedTicketList(d *models.DBInstance, c *gin.Context) {
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var tickets []models.Ticket
+	err = d.DB.
+		Scopes(models.TicketsVisibleTo(user), models.TicketsTrashed, models.TicketsWithDetails).
+		Order("tickets.deleted_at DESC, tickets.id DESC").
+		Find(&tickets).Error
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get trashed tickets",
+		})
+		return
+	}
+
+	result := []models.TicketResponse{}
+	for _, ticket := range tickets {
+		result = append(result, ticket.ToTicketResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RestoreTicket 	godoc
+//
+//	@Summary		Restore ticket
+//	@Description	Restore a deleted ticket. The ticket is placed at the bottom of its column. When its column was removed in the meantime, the ticket is placed in the first column of the board. A ticket can't be restored to a column that has reached its WIP limit unless an admin of the workspace sets override_wip_limit.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket
+//	@Router			/kanban/v1/tickets/{ticketId}/restore [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId			path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			override_wip_limit	query		bool	false	"exceed the WIP limit of the column (workspace admins only)"
+//	@Success		200					{object}	models.TicketResponse{}
+//	@Header			200					{string}	ETag	"version of the ticket"
+//	@Failure		401					{object}	models.ErrorMessage{}
+//	@Failure		403					{object}	models.ErrorMessage{}
+//	@Failure		404					{object}	models.ErrorMessage{}
+//	@Failure		409					{object}	models.WIPLimitErrorMessage{}
+//	@Failure		500					{object}	models.ErrorMessage{}
+func RestoreTicket(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTrashedTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found in trash",
+		})
+		return
+	}
+
+	rules, err := getTicketRules(d.DB, ticket.BoardID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to get ticket rules",
+		})
+		return
+	}
+
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(models.TicketsTrashed).Where("id = ?", ticket.ID).First(ticket).Error; err != nil {
+			return err
+		}
+
+		before := *ticket
+
+		// the column of the ticket could have been removed in the meantime
+		status := ticket.Status
+		if !slices.Contains(rules.Statuses, status) && len(rules.Statuses) > 0 {
+			status = rules.Statuses[0]
+		}
+
+		rank, err := getRankInColumn(tx, ticket, status, "", "")
+		if err != nil {
+			return err
+		}
+
+		ticket.Status = status
+		ticket.Rank = rank
+		ticket.DeletedAt = gorm.DeletedAt{}
+		ticket.Version++
+
+		if err := checkWIPLimit(tx, user, ticket, isWIPLimitOverridden(c)); err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Omit(clause.Associations).Save(ticket).Error; err != nil {
+			return err
+		}
+
+		return recordTicketEvents(tx, models.TICKET_EVENT_RESTORED, user, &before, ticket)
+	})
+	if abortOnTicketError(c, err, "failed to restore ticket") {
+		return
+	}
+
+	c.Header("ETag", ticket.ETag())
+	c.JSON(http.StatusOK, ticket.ToTicketResponse())
+
+	broadcastTicketEvent(d.DB, "restored", ticket.ToTicketResponse())
+}
+
+// PurgeTicket 	godoc
+//
+//	@Summary		Purge ticket
+//	@Description	Permanently delete a ticket in the trash together with its comments, history and attachments. This cannot be undone.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket
+//	@Router			/kanban/v1/tickets/{ticketId}/purge [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Success		200			{object}	models.TicketDeleteResponse{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func PurgeTicket(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTrashedTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found in trash",
+		})
+		return
+	}
+
+	// no attachments can be uploaded to tickets in the trash
+	var storageKeys []string
+	if err := d.DB.Model(&models.Attachment{}).Where("ticket_id = ?", ticket.ID).Pluck("storage_key", &storageKeys).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to purge ticket",
+		})
+		return
+	}
+
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		return purgeTicket(tx, ticket)
+	})
+	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
-			Message: "failed to delete ticket",
+			Message: "failed to purge ticket",
 		})
 		return
 	}
 
+	deleteStoredFiles(d.Storage, storageKeys...)
+
 	c.JSON(http.StatusOK, models.TicketDeleteResponse{
 		Message: "success",
 	})
 
-	websocketMessage := models.WSMessage{
-		Event:  "deleted",
-		Ticket: models.TicketResponse{},
+	broadcastTicketEvent(d.DB, "purged", ticket.ToTicketResponse())
+}
+
+// helpers
+// findTicket finds a ticket by its ID or its key
+func findTicket(db *gorm.DB, user *models.User, ticketId string) (*models.Ticket, error) {
+	return findOneTicket(db.Scopes(models.TicketsVisibleTo(user), models.TicketsByIDOrKey(ticketId), models.TicketsWithDetails))
+}
+
+func findTrashedTicket(db *gorm.DB, user *models.User, ticketId string) (*models.Ticket, error) {
+	return findOneTicket(db.Scopes(models.TicketsVisibleTo(user), models.TicketsTrashed, models.TicketsByIDOrKey(ticketId), models.TicketsWithDetails))
+}
+
+// findOneTicket returns models.ErrAmbiguousTicketKey when a key matches several tickets
+func findOneTicket(query *gorm.DB) (*models.Ticket, error) {
+	var tickets []models.Ticket
+	if err := query.Order("tickets.id").Limit(2).Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+
+	switch len(tickets) {
+	case 0:
+		return nil, gorm.ErrRecordNotFound
+	case 1:
+		return &tickets[0], nil
+	default:
+		return nil, models.ErrAmbiguousTicketKey
+	}
+}
+
+// purgeTicket permanently deletes a ticket and everything that belongs to it, except the stored files of its attachments
+func purgeTicket(db *gorm.DB, ticket *models.Ticket) error {
+	if err := db.Where("ticket_id = ?", ticket.ID).Delete(&models.Notification{}).Error; err != nil {
+		return err
+	}
+
+	if err := db.Where("ticket_id = ?", ticket.ID).Delete(&models.Mention{}).Error; err != nil {
+		return err
+	}
+
+	if err := db.Unscoped().Where("ticket_id = ?", ticket.ID).Delete(&models.Comment{}).Error; err != nil {
+		return err
+	}
+
+	if err := db.Where("ticket_id = ?", ticket.ID).Delete(&models.TicketEvent{}).Error; err != nil {
+		return err
+	}
+
+	if err := db.Where("ticket_id = ?", ticket.ID).Delete(&models.ChecklistItem{}).Error; err != nil {
+		return err
+	}
+
+	if err := db.Where("ticket_id = ? OR linked_ticket_id = ?", ticket.ID, ticket.ID).Delete(&models.TicketLink{}).Error; err != nil {
+		return err
+	}
+
+	if err := db.Where("ticket_id = ?", ticket.ID).Delete(&models.Attachment{}).Error; err != nil {
+		return err
 	}
-	msg, err := websocketMessage.ToJsonMarshal()
+
+	if err := db.Exec("DELETE FROM ticket_labels WHERE ticket_id = ?", ticket.ID).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec("DELETE FROM ticket_assignees WHERE ticket_id = ?", ticket.ID).Error; err != nil {
+		return err
+	}
+
+	return db.Unscoped().Omit(clause.Associations).Delete(ticket).Error
+}
+
+func getTicketRules(db *gorm.DB, boardId string) (models.TicketRules, error) {
+	var board models.Board
+	if err := db.Where("id = ?", boardId).First(&board).Error; err != nil {
+		return models.TicketRules{}, err
+	}
+
+	columns, err := models.GetBoardColumns(db, board.ID)
 	if err != nil {
-		logrus.Error("Failed to marshal websocket message:", err)
-	} else {
-		BroadcastMessage(msg)
+		return models.TicketRules{}, err
+	}
+
+	teams, err := models.GetTeamKeys(db, board.WorkspaceID)
+	if err != nil {
+		return models.TicketRules{}, err
+	}
+
+	members, err := models.GetWorkspaceMemberIDs(db, board.WorkspaceID)
+	if err != nil {
+		return models.TicketRules{}, err
 	}
+
+	return models.TicketRules{
+		Statuses: models.BoardColumnKeys(columns),
+		Teams:    teams,
+		Members:  members,
+	}, nil
+}
+
+// wipLimitError is returned when a ticket is added to a column that has reached its WIP limit
+type wipLimitError struct {
+	column models.BoardColumn
+	count  int64
+}
+
+func (e *wipLimitError) Error() string {
+	return fmt.Sprintf("column %q has reached its WIP limit of %d", e.column.Key, *e.column.WIPLimit)
+}
+
+var errWIPLimitOverrideForbidden = errors.New("only admins of the workspace can override WIP limits")
+
+// override_wip_limit=true lets admins of the workspace exceed the WIP limit of a column
+func isWIPLimitOverridden(c *gin.Context) bool {
+	override, _ := strconv.ParseBool(c.Query("override_wip_limit"))
+	return override
+}
+
+/*
+checkWIPLimit makes sure the column the ticket is added to has room for
+it. The column is locked until the transaction ends, so concurrent
+requests can't both take the last spot.
+*/
+func checkWIPLimit(tx *gorm.DB, user *models.User, ticket *models.Ticket, override bool) error {
+	if override {
+		var board models.Board
+		if err := tx.Where("id = ?", ticket.BoardID).First(&board).Error; err != nil {
+			return err
+		}
+		role, err := models.GetWorkspaceRole(tx, user.ID, board.WorkspaceID)
+		if err != nil || !models.HasWorkspaceRole(role, models.WORKSPACE_ROLE_ADMIN) {
+			return errWIPLimitOverrideForbidden
+		}
+		return nil
+	}
+
+	var column models.BoardColumn
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("board_id = ? AND key = ?", ticket.BoardID, ticket.Status).
+		First(&column).Error
+	if err != nil {
+		return err
+	}
+
+	if column.WIPLimit == nil {
+		return nil
+	}
+
+	var count int64
+	countQuery := tx.Model(&models.Ticket{}).Where("board_id = ? AND status = ?", ticket.BoardID, ticket.Status)
+	if ticket.ID != "" {
+		countQuery = countQuery.Where("id <> ?", ticket.ID)
+	}
+	if err := countQuery.Count(&count).Error; err != nil {
+		return err
+	}
+
+	if column.HasReachedWIPLimit(count) {
+		return &wipLimitError{column: column, count: count}
+	}
+
+	return nil
+}
+
+// getColumnWIP returns the WIP limit and the number of tickets of every column of the board
+func getColumnWIP(db *gorm.DB, boardId string) ([]models.ColumnWIPResponse, error) {
+	columns, err := models.GetBoardColumns(db, boardId)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := models.GetColumnTicketCounts(db, boardId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.ColumnWIPResponse{}
+	for _, column := range columns {
+		result = append(result, models.ColumnWIPResponse{
+			Key:      column.Key,
+			WIPLimit: column.WIPLimit,
+			Count:    counts[column.Key],
+		})
+	}
+
+	return result, nil
+}
+
+var (
+	errTicketNotFound = errors.New("ticket not found")
+	errBoardNotFound  = errors.New("board not found")
+	errTicketModified = errors.New("ticket has been modified since it was fetched")
+
+	errTicketTemplateNotFound = errors.New("template not found")
+)
+
+/*
+the ticket operations below are shared by the ticket endpoints and the
+batch endpoint. They don't write any response, errors are mapped to a
+response by ticketErrorResponse.
+*/
+
+// createTicket fills in the template, validates the request and adds the ticket at the bottom of its column
+func createTicket(db *gorm.DB, user *models.User, board *models.Board, reqBody models.TicketCreateRequest, overrideWIPLimit bool) (*models.Ticket, error) {
+	rules, err := getTicketRules(db, board.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var checklist []string
+	if reqBody.TemplateID != "" {
+		var template models.TicketTemplate
+		err := db.Where("id = ? AND board_id = ?", reqBody.TemplateID, board.ID).First(&template).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errTicketTemplateNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		reqBody = template.Apply(reqBody, time.Now())
+		checklist = template.Checklist
+	}
+
+	// new tickets start in the first column of the workflow
+	if reqBody.Status == "" && len(rules.Statuses) > 0 {
+		reqBody.Status = rules.Statuses[0]
+	}
+
+	if err := reqBody.Validate(rules); err != nil {
+		return nil, err
+	}
+
+	mentionedUsers, err := findMentionedUsers(db, board.ID, "", reqBody.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	newTicket := models.Ticket{
+		Title:       reqBody.Title,
+		Description: reqBody.Description,
+		Assignees:   reqBody.Assignees,
+		Status:      reqBody.Status,
+		DueAt:       reqBody.DueAt,
+		Priority:    reqBody.Priority,
+		Estimate:    reqBody.Estimate,
+		User:        *user,
+		BoardID:     board.ID,
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := checkWIPLimit(tx, user, &newTicket, overrideWIPLimit); err != nil {
+			return err
+		}
+
+		rank, err := getRankInColumn(tx, &newTicket, newTicket.Status, "", "")
+		if err != nil {
+			return err
+		}
+		newTicket.Rank = rank
+
+		if newTicket.AssigneeUsers, err = findAssigneeUsers(tx, reqBody.AssigneeIDs); err != nil {
+			return err
+		}
+
+		// the assigned users themselves already exist
+		if err := tx.Omit("AssigneeUsers.*").Create(&newTicket).Error; err != nil {
+			return err
+		}
+
+		if err := createChecklist(tx, &newTicket, checklist); err != nil {
+			return err
+		}
+
+		notifications, err := notifyTicketChanges(tx, user, nil, &newTicket)
+		if err != nil {
+			return err
+		}
+
+		mentionNotifications, err := createMentions(tx, user, &newTicket, nil, mentionedUsers)
+		if err != nil {
+			return err
+		}
+		newTicket.Notifications = append(notifications, mentionNotifications...)
+
+		return recordTicketEvents(tx, models.TICKET_EVENT_CREATED, user, nil, &newTicket)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &newTicket, nil
+}
+
+// patchTicket applies a JSON Merge Patch to the latest values of the ticket
+func patchTicket(db *gorm.DB, user *models.User, ticket *models.Ticket, ifMatch string, patch []byte, overrideWIPLimit bool) error {
+	rules, err := getTicketRules(db, ticket.BoardID)
+	if err != nil {
+		return err
+	}
+
+	return updateTicket(db, user, ticket, ifMatch, models.TICKET_EVENT_UPDATED, overrideWIPLimit, func(tx *gorm.DB) error {
+		current, err := json.Marshal(ticket.ToTicketUpdateRequest())
+		if err != nil {
+			return err
+		}
+
+		patched, err := patchhelper.MergePatch(current, patch)
+		if err != nil {
+			return err
+		}
+
+		var reqBody models.TicketUpdateRequest
+		if err := json.Unmarshal(patched, &reqBody); err != nil {
+			return patchhelper.ErrInvalidPatch
+		}
+
+		if err := reqBody.Validate(rules); err != nil {
+			return err
+		}
+
+		return applyTicketUpdate(tx, ticket, reqBody)
+	})
+}
+
+// moveTicket updates the status and the rank of the ticket together
+func moveTicket(db *gorm.DB, user *models.User, ticket *models.Ticket, ifMatch string, reqBody models.TicketMoveRequest, overrideWIPLimit bool) error {
+	rules, err := getTicketRules(db, ticket.BoardID)
+	if err != nil {
+		return err
+	}
+
+	if err := reqBody.Validate(rules); err != nil {
+		return err
+	}
+
+	return updateTicket(db, user, ticket, ifMatch, models.TICKET_EVENT_MOVED, overrideWIPLimit, func(tx *gorm.DB) error {
+		rank, err := getRankInColumn(tx, ticket, reqBody.Status, reqBody.BeforeID, reqBody.AfterID)
+		if err != nil {
+			return err
+		}
+
+		ticket.Status = reqBody.Status
+		ticket.Rank = rank
+
+		return nil
+	})
+}
+
+// createChecklist adds the items of a template to a new ticket in their order
+func createChecklist(db *gorm.DB, ticket *models.Ticket, texts []string) error {
+	rank := ""
+	for _, text := range texts {
+		var err error
+		if rank, err = rankhelper.After(rank); err != nil {
+			return err
+		}
+
+		item := models.ChecklistItem{
+			Text:     strings.TrimSpace(text),
+			Rank:     rank,
+			TicketID: ticket.ID,
+		}
+		if err := db.Omit("Ticket").Create(&item).Error; err != nil {
+			return err
+		}
+	}
+
+	ticket.ChecklistTotal = len(texts)
+	return nil
+}
+
+// deleteTicket moves the ticket to the trash
+func deleteTicket(db *gorm.DB, user *models.User, ticket *models.Ticket, ifMatch string) error {
+	if !matchesETag(ifMatch, ticket.ETag()) {
+		return errTicketModified
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		deleteQuery := tx
+		if ifMatch != "" {
+			// the ticket could have been changed after the If-Match check
+			deleteQuery = deleteQuery.Where("version = ?", ticket.Version)
+		}
+
+		// soft delete ticket
+		result := deleteQuery.Delete(ticket)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTicketModified
+		}
+
+		return recordTicketEvents(tx, models.TICKET_EVENT_DELETED, user, nil, ticket)
+	})
+}
+
+/*
+updateTicket locks the ticket, reloads its latest values and applies the
+change to it. The If-Match header is checked against the locked ticket, so
+a client can't overwrite changes it hasn't seen. Every update bumps the
+version of the ticket and is recorded in its history.
+*/
+func updateTicket(db *gorm.DB, user *models.User, ticket *models.Ticket, ifMatch string, action string, overrideWIPLimit bool, change func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(models.TicketsWithDetails).Where("id = ?", ticket.ID).First(ticket).Error; err != nil {
+			return err
+		}
+
+		if !matchesETag(ifMatch, ticket.ETag()) {
+			return errTicketModified
+		}
+
+		before := *ticket
+
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		if ticket.Status != before.Status {
+			if err := checkTicketBlockers(tx, ticket); err != nil {
+				return err
+			}
+
+			if err := checkWIPLimit(tx, user, ticket, overrideWIPLimit); err != nil {
+				return err
+			}
+		}
+
+		ticket.Version++
+
+		// labels are attached and detached separately
+		if err := tx.Omit(clause.Associations).Save(ticket).Error; err != nil {
+			return err
+		}
+
+		if !slices.Equal(ticket.AssigneeIDs(), before.AssigneeIDs()) {
+			if err := tx.Model(ticket).Omit("AssigneeUsers.*").Association("AssigneeUsers").Replace(ticket.AssigneeUsers); err != nil {
+				return err
+			}
+		}
+
+		notifications, err := notifyTicketChanges(tx, user, &before, ticket)
+		if err != nil {
+			return err
+		}
+
+		if ticket.Description != before.Description {
+			mentionedUsers, err := findMentionedUsers(tx, ticket.BoardID, before.Description, ticket.Description)
+			if err != nil {
+				return err
+			}
+
+			mentionNotifications, err := createMentions(tx, user, ticket, nil, mentionedUsers)
+			if err != nil {
+				return err
+			}
+			notifications = append(notifications, mentionNotifications...)
+		}
+		ticket.Notifications = notifications
+
+		return recordTicketEvents(tx, action, user, &before, ticket)
+	})
+}
+
+func recordTicketEvents(db *gorm.DB, action string, user *models.User, before *models.Ticket, after *models.Ticket) error {
+	events, err := models.NewTicketEvents(action, user.ID, before, after)
+	if err != nil {
+		return err
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	return db.Create(&events).Error
+}
+
+// applyTicketUpdate sets the new values of the ticket without saving them
+func applyTicketUpdate(db *gorm.DB, ticket *models.Ticket, reqBody models.TicketUpdateRequest) error {
+	// tickets moved to another column are placed at the bottom of that column
+	if reqBody.Status != ticket.Status {
+		rank, err := getRankInColumn(db, ticket, reqBody.Status, "", "")
+		if err != nil {
+			return err
+		}
+		ticket.Rank = rank
+	}
+
+	assigneeUsers, err := findAssigneeUsers(db, reqBody.AssigneeIDs)
+	if err != nil {
+		return err
+	}
+
+	ticket.Title = reqBody.Title
+	ticket.Description = reqBody.Description
+	ticket.Assignees = reqBody.Assignees
+	ticket.AssigneeUsers = assigneeUsers
+	ticket.Status = reqBody.Status
+	ticket.DueAt = reqBody.DueAt
+	ticket.Priority = reqBody.Priority
+	ticket.Estimate = reqBody.Estimate
+
+	return nil
+}
+
+// findAssigneeUsers loads the users of the assignee IDs, the IDs have already been validated
+func findAssigneeUsers(db *gorm.DB, userIds []string) ([]models.User, error) {
+	users := []models.User{}
+	if len(userIds) == 0 {
+		return users, nil
+	}
+
+	if err := db.Scopes(models.OrderUsers).Where("users.id IN ?", userIds).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// mentionError is returned when a text mentions users that can't see the ticket
+type mentionError struct {
+	handles []string
+}
+
+func (e *mentionError) Error() string {
+	return "mentioned users can't see the ticket: " + strings.Join(e.handles, ", ")
+}
+
+/*
+findMentionedUsers resolves the mentions that are new in a text to users.
+Every mentioned user has to be able to see the tickets of the board,
+unknown emails and usernames are rejected the same way so they don't
+reveal who has an account. Users that were already mentioned by another
+handle aren't mentioned again.
+*/
+func findMentionedUsers(db *gorm.DB, boardId string, oldText string, newText string) ([]models.User, error) {
+	handles := models.NewMentionedHandles(oldText, newText)
+
+	users, err := models.GetMentionableUsers(db, boardId, handles)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+	for _, handle := range handles {
+		if !slices.ContainsFunc(users, func(user models.User) bool { return user.IsMentionedBy(handle) }) {
+			missing = append(missing, handle)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &mentionError{handles: missing}
+	}
+
+	oldHandles := models.MentionedHandles(oldText)
+	users = slices.DeleteFunc(users, func(user models.User) bool {
+		return slices.ContainsFunc(oldHandles, user.IsMentionedBy)
+	})
+
+	return users, nil
+}
+
+// createMentions records the mentions of a ticket or a comment and notifies the mentioned users, users mentioning themselves are skipped
+func createMentions(db *gorm.DB, actor *models.User, ticket *models.Ticket, commentId *string, users []models.User) ([]models.Notification, error) {
+	mentions := []models.Mention{}
+	userIds := []string{}
+	for _, user := range users {
+		if user.ID == actor.ID {
+			continue
+		}
+
+		mentions = append(mentions, models.Mention{
+			TicketID:  ticket.ID,
+			CommentID: commentId,
+			UserID:    user.ID,
+			ActorID:   actor.ID,
+		})
+		userIds = append(userIds, user.ID)
+	}
+
+	if len(mentions) == 0 {
+		return []models.Notification{}, nil
+	}
+
+	if err := db.Omit(clause.Associations).Create(&mentions).Error; err != nil {
+		return nil, err
+	}
+
+	return notifyUsers(db, actor, ticket, models.NOTIFICATION_TYPE_MENTIONED, commentId, userIds)
+}
+
+// ticketErrorResponse maps the errors of the ticket operations to a status and a response body
+func ticketErrorResponse(err error, message string) (int, interface{}) {
+	var validationErrors validation.Errors
+	var blockedError *ticketBlockedError
+	var wipError *wipLimitError
+	var mentionErr *mentionError
+
+	switch {
+	case errors.As(err, &validationErrors):
+		return http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(validationErrors.Error(), "; "),
+		}
+	case errors.As(err, &blockedError):
+		return http.StatusConflict, models.TicketBlockedErrorMessage{
+			Message:   blockedError.Error(),
+			BlockedBy: blockedError.blockerIds,
+		}
+	case errors.As(err, &wipError):
+		return http.StatusConflict, models.WIPLimitErrorMessage{
+			Message:  wipError.Error(),
+			Status:   wipError.column.Key,
+			WIPLimit: *wipError.column.WIPLimit,
+			Count:    wipError.count,
+		}
+	case errors.Is(err, errWIPLimitOverrideForbidden), errors.Is(err, errBatchRoleForbidden):
+		return http.StatusForbidden, models.ErrorMessage{
+			Message: err.Error(),
+		}
+	case errors.As(err, &mentionErr), errors.Is(err, patchhelper.ErrInvalidPatch), errors.Is(err, errInvalidNeighbours):
+		return http.StatusBadRequest, models.ErrorMessage{
+			Message: err.Error(),
+		}
+	case errors.Is(err, errTicketNotFound), errors.Is(err, errBoardNotFound), errors.Is(err, errTicketTemplateNotFound):
+		return http.StatusNotFound, models.ErrorMessage{
+			Message: err.Error(),
+		}
+	case errors.Is(err, models.ErrAmbiguousTicketKey):
+		return http.StatusConflict, models.ErrorMessage{
+			Message: err.Error(),
+		}
+	case errors.Is(err, errTicketModified):
+		return http.StatusPreconditionFailed, models.ErrorMessage{
+			Message: err.Error(),
+		}
+	default:
+		return http.StatusInternalServerError, models.ErrorMessage{
+			Message: message,
+		}
+	}
+}
+
+// abortOnTicketError writes the response for errors of the ticket operations
+func abortOnTicketError(c *gin.Context, err error, message string) bool {
+	if err == nil {
+		return false
+	}
+
+	status, body := ticketErrorResponse(err, message)
+	c.AbortWithStatusJSON(status, body)
+
+	return true
+}
+
+// matchesETag reports whether an If-Match header allows a write to the given ETag
+func matchesETag(ifMatch string, etag string) bool {
+	if ifMatch == "" {
+		return true
+	}
+
+	for _, value := range strings.Split(ifMatch, ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || value == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+var errInvalidNeighbours = errors.New("before_id and after_id must be tickets of the target column in the right order")
+
+// getRankInColumn returns a rank that places the ticket between the given neighbours of the column
+func getRankInColumn(db *gorm.DB, ticket *models.Ticket, status string, beforeId string, afterId string) (string, error) {
+	column := func() *gorm.DB {
+		return db.Model(&models.Ticket{}).Where("board_id = ? AND status = ? AND id <> ?", ticket.BoardID, status, ticket.ID)
+	}
+
+	return getRankBetween(column, beforeId, afterId, errInvalidNeighbours)
+}
+
+/*
+getRankBetween returns a rank that places a row of the list between the
+given neighbours. When only one neighbour is given, the row is placed
+right next to it. Without neighbours, the row is placed at the bottom of
+the list. The list must not contain the row that is placed.
+errInvalid is returned when the neighbours aren't in the list or aren't
+in the right order.
+*/
+func getRankBetween(list func() *gorm.DB, beforeId string, afterId string, errInvalid error) (string, error) {
+	findNeighbourRank := func(neighbourId string) (string, error) {
+		var ranks []string
+		if err := list().Where("id = ?", neighbourId).Pluck("rank", &ranks).Error; err != nil {
+			return "", err
+		}
+		if len(ranks) == 0 {
+			return "", errInvalid
+		}
+		return ranks[0], nil
+	}
+
+	findClosestRank := func(query *gorm.DB, order string) (string, error) {
+		var ranks []string
+		if err := query.Order(order).Limit(1).Pluck("rank", &ranks).Error; err != nil {
+			return "", err
+		}
+		if len(ranks) == 0 {
+			return "", nil
+		}
+		return ranks[0], nil
+	}
+
+	var prevRank, nextRank string
+	var err error
+	switch {
+	case beforeId != "" && afterId != "":
+		if prevRank, err = findNeighbourRank(beforeId); err != nil {
+			return "", err
+		}
+		if nextRank, err = findNeighbourRank(afterId); err != nil {
+			return "", err
+		}
+	case beforeId != "":
+		if prevRank, err = findNeighbourRank(beforeId); err != nil {
+			return "", err
+		}
+		if nextRank, err = findClosestRank(list().Where("rank > ?", prevRank), "rank ASC"); err != nil {
+			return "", err
+		}
+	case afterId != "":
+		if nextRank, err = findNeighbourRank(afterId); err != nil {
+			return "", err
+		}
+		if prevRank, err = findClosestRank(list().Where("rank < ?", nextRank), "rank DESC"); err != nil {
+			return "", err
+		}
+	default:
+		if prevRank, err = findClosestRank(list(), "rank DESC"); err != nil {
+			return "", err
+		}
+	}
+
+	rank, err := rankhelper.Between(prevRank, nextRank)
+	if errors.Is(err, rankhelper.ErrInvalidOrder) {
+		return "", errInvalid
+	}
+
+	return rank, err
 }
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetTicketHistory 	godoc
+//
+//	@Summary		Get the history of a ticket
+//	@Description	Get a page of the changes made to a ticket, newest first. Every changed field is a separate event. Use next_cursor as the cursor query param to get the next page.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket
+//	@Router			/kanban/v1/tickets/{ticketId}/history [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			limit		query		int		false	"page size"	minimum(1)	maximum(100)	default(50)
+//	@Param			cursor		query		string	false	"next_cursor of the previous page"
+//	@Success		200			{object}	models.TicketEventListResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func GetTicketHistory(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	var query models.TicketEventListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid query params",
+		})
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+	query = query.WithDefaults()
+
+	var cursor *models.Cursor
+	if query.Cursor != "" {
+		// already validated
+		cursor, _ = models.DecodeCursor(query.Cursor)
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	var events []models.TicketEvent
+	if err := d.DB.Preload("Actor").Where("ticket_events.ticket_id = ?", ticket.ID).Scopes(query.Page(cursor)).Find(&events).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get ticket history",
+		})
+		return
+	}
+
+	result := models.TicketEventListResponse{
+		Data: []models.TicketEventResponse{},
+	}
+
+	// one more event than the limit means there is a next page
+	if len(events) > query.Limit {
+		events = events[:query.Limit]
+		lastEvent := events[len(events)-1]
+		result.NextCursor = models.Cursor{
+			Value: lastEvent.CreatedAt.Format(time.RFC3339Nano),
+			ID:    lastEvent.ID,
+		}.Encode()
+	}
+
+	for _, event := range events {
+		result.Data = append(result.Data, event.ToTicketEventResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
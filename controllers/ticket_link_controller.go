@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateTicketLink 	godoc
+//
+//	@Summary		Link tickets
+//	@Description	Link the ticket to another ticket, read as "ticket <type> linked ticket". The user needs the member role in the workspaces of both tickets. Links of type blocks and duplicates can't form a cycle. A blocked ticket can't leave the first column of its board until all its blockers are in the last column of theirs.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket Link
+//	@Router			/kanban/v1/tickets/{ticketId}/links [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string						true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			requestBody	body		models.TicketLinkRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.TicketLinkResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		409			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateTicketLink(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	var reqBody models.TicketLinkRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	linkedTicket, err := findTicket(d.DB, user, reqBody.TicketID)
+	if errors.Is(err, models.ErrAmbiguousTicketKey) {
+		c.AbortWithStatusJSON(http.StatusConflict, models.ErrorMessage{
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "linked ticket not found",
+		})
+		return
+	}
+
+	// the link changes the linked ticket too, so it's only linked by members of its workspace
+	var linkedBoard models.Board
+	if err := d.DB.Where("id = ?", linkedTicket.BoardID).First(&linkedBoard).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to link tickets",
+		})
+		return
+	}
+
+	role, err := models.GetWorkspaceRole(d.DB, user.ID, linkedBoard.WorkspaceID)
+	if err != nil || !models.HasWorkspaceRole(role, models.WORKSPACE_ROLE_MEMBER) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorMessage{
+			Message: "requires the " + models.WORKSPACE_ROLE_MEMBER + " role in the workspace of the linked ticket",
+		})
+		return
+	}
+
+	if linkedTicket.ID == ticket.ID {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "a ticket can't be linked to itself",
+		})
+		return
+	}
+
+	newLink := models.TicketLink{
+		Type:           reqBody.Type,
+		TicketID:       ticket.ID,
+		LinkedTicketID: linkedTicket.ID,
+		UserID:         user.ID,
+	}
+
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		// links are created one at a time, so two links can't close a cycle together
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('ticket_links'))").Error; err != nil {
+			return err
+		}
+
+		if isTicketLinked(tx, newLink) {
+			return errTicketsLinked
+		}
+
+		if newLink.Type != models.TICKET_LINK_RELATES_TO {
+			cycle, err := models.TicketLinkCreatesCycle(tx, newLink.Type, newLink.TicketID, newLink.LinkedTicketID)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return errTicketLinkCycle
+			}
+		}
+
+		return tx.Omit("Ticket", "LinkedTicket", "User").Create(&newLink).Error
+	})
+	if errors.Is(err, errTicketsLinked) || errors.Is(err, errTicketLinkCycle) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to link tickets",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newLink.ToTicketLinkResponse())
+}
+
+// GetTicketLinks 	godoc
+//
+//	@Summary		Get the links of a ticket
+//	@Description	Get the tickets linked to a ticket grouped by the way they are linked. Tickets in the trash are left out.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket Link
+//	@Router			/kanban/v1/tickets/{ticketId}/links [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Success		200			{object}	models.TicketLinksResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func GetTicketLinks(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	links, err := getTicketLinks(d.DB, ticket.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get ticket links",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// DeleteTicketLink 	godoc
+//
+//	@Summary		Unlink tickets
+//	@Description	Delete a link from or to a ticket
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket Link
+//	@Router			/kanban/v1/tickets/{ticketId}/links/{linkId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			ticketId	path		string	true	"Ticket ID or key (e.g. KAN-42), keys matching tickets in several workspaces are rejected with 409"
+//	@Param			linkId		path		string	true	"Link ID"
+//	@Success		200			{object}	models.TicketLinkDeleteResponse{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func DeleteTicketLink(d *models.DBInstance, c *gin.Context) {
+	ticketId := c.Param("ticketId")
+	linkId := c.Param("linkId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	ticket, err := findTicket(d.DB, user, ticketId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "ticket not found",
+		})
+		return
+	}
+
+	var link models.TicketLink
+	if err := d.DB.Scopes(models.TicketLinksOf(ticket.ID)).Where("ticket_links.id = ?", linkId).First(&link).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "link not found",
+		})
+		return
+	}
+
+	if err := d.DB.Omit("Ticket", "LinkedTicket", "User").Delete(&link).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to unlink tickets",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TicketLinkDeleteResponse{
+		Message: "success",
+	})
+}
+
+// helpers
+var (
+	errTicketsLinked   = errors.New("tickets are already linked")
+	errTicketLinkCycle = errors.New("link would create a cycle")
+)
+
+func getTicketLinks(db *gorm.DB, ticketId string) (models.TicketLinksResponse, error) {
+	var links []models.TicketLink
+	if err := db.Scopes(models.TicketLinksOf(ticketId)).Find(&links).Error; err != nil {
+		return models.TicketLinksResponse{}, err
+	}
+
+	return models.NewTicketLinksResponse(ticketId, links), nil
+}
+
+// relates_to links have no direction, so they are looked up both ways
+func isTicketLinked(db *gorm.DB, link models.TicketLink) bool {
+	query := db.Model(&models.TicketLink{}).Where("ticket_links.type = ?", link.Type)
+	if link.Type == models.TICKET_LINK_RELATES_TO {
+		query = query.Where(
+			"(ticket_links.ticket_id = ? AND ticket_links.linked_ticket_id = ?) OR (ticket_links.ticket_id = ? AND ticket_links.linked_ticket_id = ?)",
+			link.TicketID, link.LinkedTicketID, link.LinkedTicketID, link.TicketID,
+		)
+	} else {
+		query = query.Where("ticket_links.ticket_id = ? AND ticket_links.linked_ticket_id = ?", link.TicketID, link.LinkedTicketID)
+	}
+
+	var count int64
+	query.Count(&count)
+	return count > 0
+}
+
+// ticketBlockedError is returned when a blocked ticket is started before its blockers are done
+type ticketBlockedError struct {
+	blockerIds []string
+}
+
+func (e *ticketBlockedError) Error() string {
+	return "ticket is blocked by tickets that are not done"
+}
+
+/*
+checkTicketBlockers keeps a blocked ticket in the first column of its
+board until all its blockers are done. Moving a ticket back to the first
+column is always allowed.
+*/
+func checkTicketBlockers(db *gorm.DB, ticket *models.Ticket) error {
+	columns, err := models.GetBoardColumns(db, ticket.BoardID)
+	if err != nil {
+		return err
+	}
+	if len(columns) > 0 && ticket.Status == columns[0].Key {
+		return nil
+	}
+
+	blockerIds, err := models.GetOpenBlockerIDs(db, ticket.ID)
+	if err != nil {
+		return err
+	}
+	if len(blockerIds) > 0 {
+		return &ticketBlockedError{blockerIds: blockerIds}
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateTicketTemplate 	godoc
+//
+//	@Summary		Create ticket template
+//	@Description	Create a template that pre-fills new tickets of the board. The title pattern can contain {title} for the title given when the ticket is created and {date} for the current date, e.g. "[Bug] {title}" or "Release {date}".
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket Template
+//	@Router			/kanban/v1/boards/{boardId}/templates [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			boardId		path		string							true	"Board ID"
+//	@Param			requestBody	body		models.TicketTemplateRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.TicketTemplateResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateTicketTemplate(d *models.DBInstance, c *gin.Context) {
+	boardId := c.Param("boardId")
+
+	var reqBody models.TicketTemplateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	board, err := findBoard(d.DB, user, boardId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "board not found",
+		})
+		return
+	}
+
+	if abortOnInvalidTicketTemplate(d.DB, c, board.ID, reqBody, "") {
+		return
+	}
+
+	newTemplate := models.TicketTemplate{BoardID: board.ID}
+	applyTicketTemplateRequest(&newTemplate, reqBody)
+
+	if err := d.DB.Create(&newTemplate).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create template",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newTemplate.ToTicketTemplateResponse())
+}
+
+// GetTicketTemplateList 	godoc
+//
+//	@Summary		Get a list of ticket templates
+//	@Description	Get the ticket templates of a board, ordered by name
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket Template
+//	@Router			/kanban/v1/boards/{boardId}/templates [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			boardId	path		string	true	"Board ID"
+//	@Success		200		{object}	[]models.TicketTemplateResponse{}
+//	@Failure		400		{object}	models.ErrorMessage{}
+//	@Failure		401		{object}	models.ErrorMessage{}
+//	@Failure		404		{object}	models.ErrorMessage{}
+func GetTicketTemplateList(d *models.DBInstance, c *gin.Context) {
+	boardId := c.Param("boardId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	board, err := findBoard(d.DB, user, boardId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "board not found",
+		})
+		return
+	}
+
+	var templates []models.TicketTemplate
+	if err := d.DB.Where("board_id = ?", board.ID).Order("name ASC").Find(&templates).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get all templates",
+		})
+		return
+	}
+
+	result := []models.TicketTemplateResponse{}
+	for _, template := range templates {
+		result = append(result, template.ToTicketTemplateResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetTicketTemplateById 	godoc
+//
+//	@Summary		Get ticket template by the template ID
+//	@Description	Get ticket template by the template ID
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket Template
+//	@Router			/kanban/v1/templates/{templateId} [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			templateId	path		string	true	"Template ID"
+//	@Success		200			{object}	models.TicketTemplateResponse{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func GetTicketTemplateById(d *models.DBInstance, c *gin.Context) {
+	templateId := c.Param("templateId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	template, err := findTicketTemplate(d.DB, user, templateId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "template not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, template.ToTicketTemplateResponse())
+}
+
+// UpdateTicketTemplate 	godoc
+//
+//	@Summary		Update a ticket template
+//	@Description	Replace all fields of a ticket template. Tickets created from the template before stay untouched.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket Template
+//	@Router			/kanban/v1/templates/{templateId} [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			templateId	path		string							true	"Template ID"
+//	@Param			requestBody	body		models.TicketTemplateRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.TicketTemplateResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func UpdateTicketTemplate(d *models.DBInstance, c *gin.Context) {
+	templateId := c.Param("templateId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.TicketTemplateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	template, err := findTicketTemplate(d.DB, user, templateId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "template not found",
+		})
+		return
+	}
+
+	if abortOnInvalidTicketTemplate(d.DB, c, template.BoardID, reqBody, template.ID) {
+		return
+	}
+
+	applyTicketTemplateRequest(template, reqBody)
+
+	if err := d.DB.Save(template).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to update template",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, template.ToTicketTemplateResponse())
+}
+
+// DeleteTicketTemplate 	godoc
+//
+//	@Summary		Delete ticket template
+//	@Description	Delete a ticket template. Tickets created from the template stay untouched.
+//	@Security		ApiKeyAuth
+//	@Tags			Ticket Template
+//	@Router			/kanban/v1/templates/{templateId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			templateId	path		string	true	"Template ID"
+//	@Success		200			{object}	models.TicketTemplateDeleteResponse{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func DeleteTicketTemplate(d *models.DBInstance, c *gin.Context) {
+	templateId := c.Param("templateId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	template, err := findTicketTemplate(d.DB, user, templateId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "template not found",
+		})
+		return
+	}
+
+	if err := d.DB.Delete(template).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to delete template",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TicketTemplateDeleteResponse{
+		Message: "success",
+	})
+}
+
+// helpers
+func findTicketTemplate(db *gorm.DB, user *models.User, templateId string) (*models.TicketTemplate, error) {
+	var template models.TicketTemplate
+	if err := db.Scopes(models.TicketTemplatesVisibleTo(user)).Where("ticket_templates.id = ?", templateId).First(&template).Error; err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func isTicketTemplateNameUsed(db *gorm.DB, boardId string, name string, exceptTemplateId string) bool {
+	var count int64
+	db.Model(&models.TicketTemplate{}).Where("board_id = ? AND name = ? AND id <> ?", boardId, name, exceptTemplateId).Count(&count)
+	return count > 0
+}
+
+// templates are validated against the workflow and the teams of their board
+func abortOnInvalidTicketTemplate(db *gorm.DB, c *gin.Context, boardId string, reqBody models.TicketTemplateRequest, exceptTemplateId string) bool {
+	rules, err := getTicketRules(db, boardId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to get ticket rules",
+		})
+		return true
+	}
+
+	if err := reqBody.Validate(rules); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return true
+	}
+
+	if isTicketTemplateNameUsed(db, boardId, reqBody.Name, exceptTemplateId) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "template name is already used",
+		})
+		return true
+	}
+
+	return false
+}
+
+func applyTicketTemplateRequest(template *models.TicketTemplate, reqBody models.TicketTemplateRequest) {
+	template.Name = reqBody.Name
+	template.TitlePattern = reqBody.TitlePattern
+	template.Description = reqBody.Description
+	template.Assignees = reqBody.Assignees
+	template.Status = reqBody.Status
+	template.Priority = reqBody.Priority
+	template.Estimate = reqBody.Estimate
+	template.Checklist = reqBody.Checklist
+}
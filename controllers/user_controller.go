@@ -8,12 +8,13 @@ import (
 	"github.com/Manuel-Leleuly/kanban-flow-go/models"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // CreateUser 		godoc
 //
 //	@Summary		Create user
-//	@Description	Create a user
+//	@Description	Create a user. Other users mention the user by the username, e.g. @jane_doe. It is derived from the email when left out. With invitation_token, the invitation to a workspace is accepted with the new account, the email has to be the email the invitation was sent to.
 //	@Tags			User
 //	@Router			/iam/v1/users [post]
 //	@Accept			json
@@ -21,6 +22,8 @@ import (
 //	@Param			requestBody	body		models.UserCreateRequest{}	true	"Request Body"
 //	@Success		201			{object}	models.UserResponse{}
 //	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
 //	@Failure		500			{object}	models.ErrorMessage{}
 func CreateUser(d *models.DBInstance, c *gin.Context) {
 	var reqBody models.UserCreateRequest
@@ -47,6 +50,23 @@ func CreateUser(d *models.DBInstance, c *gin.Context) {
 		return
 	}
 
+	if reqBody.Username != "" {
+		taken, err := models.IsUsernameTaken(d.DB, reqBody.Username)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+				Message: "failed to create user",
+			})
+			return
+		}
+
+		if taken {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+				Message: "username is already used",
+			})
+			return
+		}
+	}
+
 	hash, err := bcrypt.GenerateFromPassword([]byte(reqBody.Password), bcrypt.DefaultCost)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
@@ -55,16 +75,34 @@ func CreateUser(d *models.DBInstance, c *gin.Context) {
 		return
 	}
 
+	var invitation *models.Invitation
+	if reqBody.InvitationToken != "" {
+		invitation, err = findInvitationByToken(d.DB, reqBody.InvitationToken, reqBody.Email)
+		if abortOnInvitationError(c, err, "failed to create user") {
+			return
+		}
+	}
+
 	newUser := models.User{
 		FirstName: reqBody.FirstName,
 		LastName:  reqBody.LastName,
 		Email:     reqBody.Email,
+		Username:  reqBody.Username,
 		Password:  string(hash),
 	}
-	if d.DB.Create(&newUser).Error != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
-			Message: "failed to create user",
-		})
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newUser).Error; err != nil {
+			return err
+		}
+		if _, err := models.GetPersonalWorkspace(tx, newUser.ID); err != nil {
+			return err
+		}
+		if invitation != nil {
+			return acceptInvitation(tx, invitation, &newUser)
+		}
+		return nil
+	})
+	if abortOnInvitationError(c, err, "failed to create user") {
 		return
 	}
 
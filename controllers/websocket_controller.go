@@ -3,9 +3,15 @@ package controllers
 import (
 	"net/http"
 	"os"
+	"slices"
+	"sync"
 
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 var upgrader = websocket.Upgrader{
@@ -21,27 +27,140 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-var clients = make(map[*websocket.Conn]bool)
+// clients and the ID of their user, only signed in users can connect
+var clients = make(map[*websocket.Conn]string)
+
+// guards the clients, a connection also only supports one writer at a time
+var clientsMutex sync.Mutex
 
 func WebSocketHandler(c *gin.Context) {
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		return
 	}
 	defer conn.Close()
-	clients[conn] = true
+
+	clientsMutex.Lock()
+	clients[conn] = user.ID
+	clientsMutex.Unlock()
 
 	for {
 		if _, _, err := conn.ReadMessage(); err != nil {
+			clientsMutex.Lock()
 			delete(clients, conn)
+			clientsMutex.Unlock()
 			return
 		}
 	}
 }
 
-// Broadcast message to all clients
-func BroadcastMessage(message []byte) {
-	for client := range clients {
-		client.WriteMessage(websocket.TextMessage, message)
+// Send message to the clients of the given users
+func SendMessageToUsers(userIds []string, message []byte) {
+	clientsMutex.Lock()
+	defer clientsMutex.Unlock()
+
+	for client, clientUserId := range clients {
+		if slices.Contains(userIds, clientUserId) {
+			client.WriteMessage(websocket.TextMessage, message)
+		}
+	}
+}
+
+// Broadcast ticket event to the members of the workspace of the ticket
+func broadcastTicketEvent(db *gorm.DB, event string, ticket models.TicketResponse) {
+	broadcastWSMessage(db, ticket.BoardID, models.WSMessage{
+		Event:  event,
+		Ticket: &ticket,
+	})
+}
+
+// Broadcast comment event to the members of the workspace of the ticket of the comment
+func broadcastCommentEvent(db *gorm.DB, boardId string, event string, comment models.CommentResponse) {
+	broadcastWSMessage(db, boardId, models.WSMessage{
+		Event:   event,
+		Comment: &comment,
+	})
+}
+
+/*
+broadcastBatchEvents sends a single batch event to every member of the
+workspaces of the tickets of the events. Members only get the events of
+the workspaces they are a member of.
+*/
+func broadcastBatchEvents(db *gorm.DB, events []models.WSMessage) {
+	memberIds := map[string][]string{}
+	userEvents := map[string][]models.WSMessage{}
+	userIds := []string{}
+	for _, event := range events {
+		boardId := event.Ticket.BoardID
+		if _, ok := memberIds[boardId]; !ok {
+			ids, err := models.GetBoardMemberIDs(db, boardId)
+			if err != nil {
+				logrus.Error("Failed to get websocket recipients:", err)
+			}
+			memberIds[boardId] = ids
+		}
+
+		for _, userId := range memberIds[boardId] {
+			if _, ok := userEvents[userId]; !ok {
+				userIds = append(userIds, userId)
+			}
+			userEvents[userId] = append(userEvents[userId], event)
+		}
 	}
+
+	for _, userId := range userIds {
+		msg, err := (&models.WSMessage{
+			Event:  "batch",
+			Events: userEvents[userId],
+		}).ToJsonMarshal()
+		if err != nil {
+			logrus.Error("Failed to marshal websocket message:", err)
+			continue
+		}
+
+		SendMessageToUsers([]string{userId}, msg)
+	}
+}
+
+// Send every notification to the clients of its recipient only
+func pushNotifications(notifications []models.Notification) {
+	for _, notification := range notifications {
+		notificationResponse := notification.ToNotificationResponse()
+		msg, err := (&models.WSMessage{
+			Event:        "notification.created",
+			Notification: &notificationResponse,
+		}).ToJsonMarshal()
+		if err != nil {
+			logrus.Error("Failed to marshal websocket message:", err)
+			continue
+		}
+
+		SendMessageToUsers([]string{notification.UserID}, msg)
+	}
+}
+
+// broadcastWSMessage sends the message to the members of the workspace of the board only
+func broadcastWSMessage(db *gorm.DB, boardId string, websocketMessage models.WSMessage) {
+	memberIds, err := models.GetBoardMemberIDs(db, boardId)
+	if err != nil {
+		logrus.Error("Failed to get websocket recipients:", err)
+		return
+	}
+
+	msg, err := websocketMessage.ToJsonMarshal()
+	if err != nil {
+		logrus.Error("Failed to marshal websocket message:", err)
+		return
+	}
+
+	SendMessageToUsers(memberIds, msg)
 }
@@ -0,0 +1,567 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateWorkspace 	godoc
+//
+//	@Summary		Create workspace
+//	@Description	Create a workspace to share boards with other users. The user who creates the workspace becomes its owner.
+//	@Security		ApiKeyAuth
+//	@Tags			Workspace
+//	@Router			/kanban/v1/workspaces [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			requestBody	body		models.WorkspaceRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.WorkspaceResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateWorkspace(d *models.DBInstance, c *gin.Context) {
+	var reqBody models.WorkspaceRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	workspace, err := models.CreateWorkspace(d.DB, user.ID, reqBody.Name)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to create workspace",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, workspace.ToWorkspaceResponse(models.WORKSPACE_ROLE_OWNER))
+}
+
+// GetWorkspaceList 	godoc
+//
+//	@Summary		Get a list of workspaces
+//	@Description	Get the workspaces the user stored in the token is a member of, together with the role of the user
+//	@Security		ApiKeyAuth
+//	@Tags			Workspace
+//	@Router			/kanban/v1/workspaces [get]
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	[]models.WorkspaceResponse{}
+//	@Failure		400	{object}	models.ErrorMessage{}
+//	@Failure		401	{object}	models.ErrorMessage{}
+func GetWorkspaceList(d *models.DBInstance, c *gin.Context) {
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var memberships []models.Membership
+	if err := d.DB.Preload("Workspace").Where("user_id = ?", user.ID).Find(&memberships).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get all workspaces",
+		})
+		return
+	}
+
+	result := []models.WorkspaceResponse{}
+	for _, membership := range memberships {
+		result = append(result, membership.Workspace.ToWorkspaceResponse(membership.Role))
+	}
+
+	// oldest first, the personal workspace usually comes first
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetWorkspaceById 	godoc
+//
+//	@Summary		Get workspace by the workspace ID
+//	@Description	Get workspace by the workspace ID
+//	@Security		ApiKeyAuth
+//	@Tags			Workspace
+//	@Router			/kanban/v1/workspaces/{workspaceId} [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspaceId	path		string	true	"Workspace ID"
+//	@Success		200			{object}	models.WorkspaceResponse{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func GetWorkspaceById(d *models.DBInstance, c *gin.Context) {
+	workspaceId := c.Param("workspaceId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	workspace, role, err := findWorkspace(d.DB, user, workspaceId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "workspace not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, workspace.ToWorkspaceResponse(role))
+}
+
+// UpdateWorkspace 	godoc
+//
+//	@Summary		Update a workspace
+//	@Description	Rename a workspace. Requires the admin role.
+//	@Security		ApiKeyAuth
+//	@Tags			Workspace
+//	@Router			/kanban/v1/workspaces/{workspaceId} [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspaceId	path		string						true	"Workspace ID"
+//	@Param			requestBody	body		models.WorkspaceRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.WorkspaceResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func UpdateWorkspace(d *models.DBInstance, c *gin.Context) {
+	workspaceId := c.Param("workspaceId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.WorkspaceRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	workspace, role, err := findWorkspace(d.DB, user, workspaceId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "workspace not found",
+		})
+		return
+	}
+
+	workspace.Name = reqBody.Name
+
+	if err := d.DB.Save(workspace).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to update workspace",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, workspace.ToWorkspaceResponse(role))
+}
+
+// GetWorkspaceMemberList 	godoc
+//
+//	@Summary		Get a list of workspace members
+//	@Description	Get the members of a workspace and their roles, ordered by the time they joined
+//	@Security		ApiKeyAuth
+//	@Tags			Workspace
+//	@Router			/kanban/v1/workspaces/{workspaceId}/members [get]
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspaceId	path		string	true	"Workspace ID"
+//	@Success		200			{object}	[]models.MembershipResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+func GetWorkspaceMemberList(d *models.DBInstance, c *gin.Context) {
+	workspaceId := c.Param("workspaceId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	workspace, _, err := findWorkspace(d.DB, user, workspaceId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "workspace not found",
+		})
+		return
+	}
+
+	var memberships []models.Membership
+	if err := d.DB.Preload("User").Where("workspace_id = ?", workspace.ID).Order("created_at ASC").Find(&memberships).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "failed to get all members",
+		})
+		return
+	}
+
+	result := []models.MembershipResponse{}
+	for _, membership := range memberships {
+		result = append(result, membership.ToMembershipResponse())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateWorkspaceMember 	godoc
+//
+//	@Summary		Add workspace member
+//	@Description	Add an existing user to a workspace by their email. Requires the admin role, only owners can add owners.
+//	@Security		ApiKeyAuth
+//	@Tags			Workspace
+//	@Router			/kanban/v1/workspaces/{workspaceId}/members [post]
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspaceId	path		string								true	"Workspace ID"
+//	@Param			requestBody	body		models.MembershipCreateRequest{}	true	"Request Body"
+//	@Success		201			{object}	models.MembershipResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func CreateWorkspaceMember(d *models.DBInstance, c *gin.Context) {
+	workspaceId := c.Param("workspaceId")
+
+	var reqBody models.MembershipCreateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	workspace, role, err := findWorkspace(d.DB, user, workspaceId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "workspace not found",
+		})
+		return
+	}
+
+	if reqBody.Role == models.WORKSPACE_ROLE_OWNER && role != models.WORKSPACE_ROLE_OWNER {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorMessage{
+			Message: errOwnerRoleForbidden.Error(),
+		})
+		return
+	}
+
+	var newMember models.User
+	if err := d.DB.Where("email = ?", reqBody.Email).First(&newMember).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "user not found",
+		})
+		return
+	}
+
+	if _, err := models.GetWorkspaceRole(d.DB, newMember.ID, workspace.ID); err == nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: errAlreadyMember.Error(),
+		})
+		return
+	}
+
+	newMembership := models.Membership{
+		WorkspaceID: workspace.ID,
+		UserID:      newMember.ID,
+		Role:        reqBody.Role,
+	}
+
+	if err := d.DB.Omit("Workspace", "User").Create(&newMembership).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to add member",
+		})
+		return
+	}
+
+	newMembership.User = newMember
+
+	c.JSON(http.StatusCreated, newMembership.ToMembershipResponse())
+}
+
+// UpdateWorkspaceMember 	godoc
+//
+//	@Summary		Update the role of a workspace member
+//	@Description	Change the role of a member of a workspace. Requires the admin role, only owners can change the role of owners or make other members owners. The last owner of a workspace can't be demoted.
+//	@Security		ApiKeyAuth
+//	@Tags			Workspace
+//	@Router			/kanban/v1/workspaces/{workspaceId}/members/{userId} [put]
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspaceId	path		string								true	"Workspace ID"
+//	@Param			userId		path		string								true	"User ID of the member"
+//	@Param			requestBody	body		models.MembershipUpdateRequest{}	true	"Request Body"
+//	@Success		200			{object}	models.MembershipResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func UpdateWorkspaceMember(d *models.DBInstance, c *gin.Context) {
+	workspaceId := c.Param("workspaceId")
+	userId := c.Param("userId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	var reqBody models.MembershipUpdateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "invalid request body",
+		})
+		return
+	}
+
+	if err := reqBody.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ValidationErrorMessage{
+			Message: strings.Split(err.Error(), "; "),
+		})
+		return
+	}
+
+	workspace, role, err := findWorkspace(d.DB, user, workspaceId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "workspace not found",
+		})
+		return
+	}
+
+	membership, err := findMembership(d.DB, workspace.ID, userId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "member not found",
+		})
+		return
+	}
+
+	if abortOnMembershipChange(d.DB, c, role, membership, reqBody.Role) {
+		return
+	}
+
+	membership.Role = reqBody.Role
+
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(membership).Update("role", membership.Role).Error; err != nil {
+			return err
+		}
+
+		// a personal workspace is only personal while its user owns it
+		if membership.Role != models.WORKSPACE_ROLE_OWNER {
+			return models.LeavePersonalWorkspace(tx, membership.UserID, workspace.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to update member",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, membership.ToMembershipResponse())
+}
+
+// DeleteWorkspaceMember 	godoc
+//
+//	@Summary		Remove workspace member
+//	@Description	Remove a member from a workspace. The member is unassigned from all tickets of the workspace. Requires the admin role, only owners can remove owners. The last owner of a workspace can't be removed.
+//	@Security		ApiKeyAuth
+//	@Tags			Workspace
+//	@Router			/kanban/v1/workspaces/{workspaceId}/members/{userId} [delete]
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspaceId	path		string	true	"Workspace ID"
+//	@Param			userId		path		string	true	"User ID of the member"
+//	@Success		200			{object}	models.MembershipDeleteResponse{}
+//	@Failure		400			{object}	models.ErrorMessage{}
+//	@Failure		401			{object}	models.ErrorMessage{}
+//	@Failure		403			{object}	models.ErrorMessage{}
+//	@Failure		404			{object}	models.ErrorMessage{}
+//	@Failure		500			{object}	models.ErrorMessage{}
+func DeleteWorkspaceMember(d *models.DBInstance, c *gin.Context) {
+	workspaceId := c.Param("workspaceId")
+	userId := c.Param("userId")
+
+	user, err := context.GetUserFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+			Message: "unauthorized access",
+		})
+		return
+	}
+
+	workspace, role, err := findWorkspace(d.DB, user, workspaceId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "workspace not found",
+		})
+		return
+	}
+
+	membership, err := findMembership(d.DB, workspace.ID, userId)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorMessage{
+			Message: "member not found",
+		})
+		return
+	}
+
+	if abortOnMembershipChange(d.DB, c, role, membership, "") {
+		return
+	}
+
+	err = d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(membership).Error; err != nil {
+			return err
+		}
+
+		if err := models.LeavePersonalWorkspace(tx, membership.UserID, workspace.ID); err != nil {
+			return err
+		}
+
+		// former members can't see the tickets anymore
+		return models.UnassignWorkspaceTickets(tx, membership.UserID, workspace.ID)
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to remove member",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MembershipDeleteResponse{
+		Message: "success",
+	})
+}
+
+// helpers
+var errOwnerRoleForbidden = errors.New("only owners can grant, change or remove the owner role")
+
+// findWorkspace returns the workspace together with the role of the user in it
+func findWorkspace(db *gorm.DB, user *models.User, workspaceId string) (*models.Workspace, string, error) {
+	var workspace models.Workspace
+	if err := db.Scopes(models.WorkspacesVisibleTo(user)).Where("workspaces.id = ?", workspaceId).First(&workspace).Error; err != nil {
+		return nil, "", err
+	}
+
+	role, err := models.GetWorkspaceRole(db, user.ID, workspace.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &workspace, role, nil
+}
+
+func findMembership(db *gorm.DB, workspaceId string, userId string) (*models.Membership, error) {
+	var membership models.Membership
+	if err := db.Preload("User").Where("workspace_id = ? AND user_id = ?", workspaceId, userId).First(&membership).Error; err != nil {
+		return nil, err
+	}
+
+	return &membership, nil
+}
+
+/*
+abortOnMembershipChange makes sure the member with the given role may
+change the membership to the new role, an empty role removes the
+membership. Owners are managed by owners only and the last owner has to
+stay.
+*/
+func abortOnMembershipChange(db *gorm.DB, c *gin.Context, role string, membership *models.Membership, newRole string) bool {
+	ownerChanged := membership.Role == models.WORKSPACE_ROLE_OWNER || newRole == models.WORKSPACE_ROLE_OWNER
+	if ownerChanged && role != models.WORKSPACE_ROLE_OWNER {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorMessage{
+			Message: errOwnerRoleForbidden.Error(),
+		})
+		return true
+	}
+
+	if membership.Role != models.WORKSPACE_ROLE_OWNER || newRole == models.WORKSPACE_ROLE_OWNER {
+		return false
+	}
+
+	owners, err := models.CountWorkspaceOwners(db, membership.WorkspaceID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+			Message: "failed to count owners",
+		})
+		return true
+	}
+
+	if owners <= 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorMessage{
+			Message: "the workspace must keep at least one owner",
+		})
+		return true
+	}
+
+	return false
+}
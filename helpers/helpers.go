@@ -1,6 +1,8 @@
 package helpers
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -19,3 +21,13 @@ func GetBaseUrl(c *gin.Context) string {
 func GenerateUUIDWithoutHyphen() string {
 	return strings.ReplaceAll(uuid.NewString(), "-", "")
 }
+
+// GenerateSecretToken returns a random token that is long enough to be unguessable
+func GenerateSecretToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
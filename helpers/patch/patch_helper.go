@@ -0,0 +1,56 @@
+package patchhelper
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidPatch = errors.New("patch must be a JSON object")
+
+/*
+MergePatch applies a JSON Merge Patch (RFC 7396) to a JSON document:
+- fields in the patch replace the fields of the document
+- fields set to null in the patch are removed from the document
+- nested objects are merged recursively
+- fields that are not in the patch are left untouched
+*/
+func MergePatch(document []byte, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, ErrInvalidPatch
+	}
+
+	// a patch that isn't an object would replace the whole document
+	if _, ok := patchValue.(map[string]interface{}); !ok {
+		return nil, ErrInvalidPatch
+	}
+
+	return json.Marshal(mergeValue(target, patchValue))
+}
+
+func mergeValue(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+
+	return targetObject
+}
@@ -0,0 +1,245 @@
+package queryhelper
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/*
+A query is a list of terms separated by spaces. All terms must match.
+
+	status:doing assignee:backend -label:wontfix updated>-7d "login bug"
+
+- field:value matches a field, field:a,b matches any of the values
+- field>value, field>=value, field<value and field<=value compare a field
+- a term starting with "-" must not match
+- other words and "quoted phrases" are free text
+- values with spaces can be quoted, for example label:"needs review"
+*/
+
+// operators ordered so that the longest operator is matched first
+var OPERATORS = []string{">=", "<=", ":", ">", "<"}
+
+type Term struct {
+	Negated bool
+	// empty for free text
+	Field    string
+	Operator string
+	// free text terms have a single value
+	Values []string
+	// free text in quotes
+	Phrase bool
+}
+
+type SyntaxError struct {
+	Position int
+	Message  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+var fieldPattern = regexp.MustCompile(`^[a-z_]+`)
+
+// Parse splits a query into its terms
+func Parse(query string) ([]Term, error) {
+	p := parser{input: []rune(query)}
+
+	var terms []Term
+	for {
+		p.skipSpaces()
+		if p.done() {
+			return terms, nil
+		}
+
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+}
+
+type parser struct {
+	input []rune
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) parseTerm() (Term, error) {
+	var term Term
+
+	// a lonely "-" is just a word
+	if p.input[p.pos] == '-' && p.pos+1 < len(p.input) && !unicode.IsSpace(p.input[p.pos+1]) {
+		term.Negated = true
+		p.pos++
+	}
+
+	if p.input[p.pos] == '"' {
+		phrase, err := p.parseQuoted()
+		if err != nil {
+			return Term{}, err
+		}
+		term.Values = []string{phrase}
+		term.Phrase = true
+		return term, nil
+	}
+
+	rest := string(p.input[p.pos:])
+	if field := fieldPattern.FindString(rest); field != "" {
+		for _, operator := range OPERATORS {
+			if !strings.HasPrefix(rest[len(field):], operator) {
+				continue
+			}
+
+			start := p.pos
+			p.pos += len([]rune(field + operator))
+
+			values, err := p.parseValues()
+			if err != nil {
+				return Term{}, err
+			}
+			if len(values) == 0 {
+				return Term{}, &SyntaxError{Position: start, Message: fmt.Sprintf("missing value for %q", field)}
+			}
+
+			term.Field = field
+			term.Operator = operator
+			term.Values = values
+			return term, nil
+		}
+	}
+
+	term.Values = []string{p.parseWord()}
+	return term, nil
+}
+
+// parseValues reads a comma separated list of values
+func (p *parser) parseValues() ([]string, error) {
+	var values []string
+	for !p.done() && !unicode.IsSpace(p.input[p.pos]) {
+		var value string
+		if p.input[p.pos] == '"' {
+			quoted, err := p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			value = quoted
+		} else {
+			start := p.pos
+			for !p.done() && !unicode.IsSpace(p.input[p.pos]) && p.input[p.pos] != ',' {
+				p.pos++
+			}
+			value = string(p.input[start:p.pos])
+		}
+
+		if value != "" {
+			values = append(values, value)
+		}
+
+		if !p.done() && p.input[p.pos] == ',' {
+			p.pos++
+		}
+	}
+	return values, nil
+}
+
+func (p *parser) parseQuoted() (string, error) {
+	start := p.pos
+	p.pos++ // opening quote
+
+	var value strings.Builder
+	for !p.done() {
+		switch r := p.input[p.pos]; {
+		case r == '"':
+			p.pos++
+			return value.String(), nil
+		case r == '\\' && p.pos+1 < len(p.input):
+			value.WriteRune(p.input[p.pos+1])
+			p.pos += 2
+		default:
+			value.WriteRune(r)
+			p.pos++
+		}
+	}
+
+	return "", &SyntaxError{Position: start, Message: "unterminated quote"}
+}
+
+func (p *parser) parseWord() string {
+	start := p.pos
+	for !p.done() && !unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+var relativeTimePattern = regexp.MustCompile(`^([+-]?)(\d+)([hdwmy])$`)
+
+/*
+ParseTime reads the time values of a query:
+- relative to now, for example -7d, -12h, +2w, -3m, -1y
+- today, yesterday and tomorrow, which start at midnight
+- a date (2006-01-02) or a timestamp (RFC 3339)
+
+The returned bool reports whether the value is a whole day.
+*/
+func ParseTime(value string, now time.Time) (time.Time, bool, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch value {
+	case "today":
+		return today, true, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true, nil
+	}
+
+	if match := relativeTimePattern.FindStringSubmatch(value); match != nil {
+		amount, err := strconv.Atoi(match[2])
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if match[1] == "-" {
+			amount = -amount
+		}
+
+		switch match[3] {
+		case "h":
+			return now.Add(time.Duration(amount) * time.Hour), false, nil
+		case "d":
+			return now.AddDate(0, 0, amount), false, nil
+		case "w":
+			return now.AddDate(0, 0, amount*7), false, nil
+		case "m":
+			return now.AddDate(0, amount, 0), false, nil
+		default:
+			return now.AddDate(amount, 0, 0), false, nil
+		}
+	}
+
+	if date, err := time.ParseInLocation(time.DateOnly, value, now.Location()); err == nil {
+		return date, true, nil
+	}
+
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, false, nil
+	}
+
+	return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+}
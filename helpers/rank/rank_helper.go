@@ -0,0 +1,134 @@
+package rankhelper
+
+import (
+	"errors"
+	"strings"
+)
+
+/*
+Ranks are strings that are ordered lexicographically (byte by byte).
+A new rank can always be generated between two existing ranks, so
+moving a ticket only changes the rank of that ticket and never
+renumbers its siblings.
+
+Ranks never end with the smallest digit ("0"), otherwise there would
+be no room left between for example "a" and "a0".
+*/
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+var (
+	ErrInvalidRank  = errors.New("invalid rank")
+	ErrInvalidOrder = errors.New("prev rank must be lower than next rank")
+)
+
+// Between returns a rank between prev and next. An empty prev means
+// the start of the list and an empty next means the end of the list.
+func Between(prev string, next string) (string, error) {
+	if !isValid(prev) || !isValid(next) {
+		return "", ErrInvalidRank
+	}
+
+	if next != "" && prev >= next {
+		return "", ErrInvalidOrder
+	}
+
+	// appending and prepending are the most common moves, so keep those ranks short
+	if prev != "" && next == "" {
+		return increment(prev), nil
+	}
+	if prev == "" && next != "" {
+		return decrement(next), nil
+	}
+
+	return midpoint(prev, next), nil
+}
+
+// After returns a rank that comes after prev
+func After(prev string) (string, error) {
+	return Between(prev, "")
+}
+
+// Before returns a rank that comes before next
+func Before(next string) (string, error) {
+	return Between("", next)
+}
+
+// helpers
+func midpoint(prev string, next string) string {
+	// keep the common prefix and find the midpoint of the remaining part
+	prefixLength := 0
+	for prefixLength < len(next) && digitAt(prev, prefixLength) == next[prefixLength] {
+		prefixLength++
+	}
+	if prefixLength > 0 {
+		return next[:prefixLength] + midpoint(tail(prev, prefixLength), next[prefixLength:])
+	}
+
+	prevDigit := 0
+	if prev != "" {
+		prevDigit = strings.IndexByte(digits, prev[0])
+	}
+
+	nextDigit := len(digits)
+	if next != "" {
+		nextDigit = strings.IndexByte(digits, next[0])
+	}
+
+	// there is a digit between the first digits
+	if nextDigit-prevDigit > 1 {
+		return string(digits[(prevDigit+nextDigit+1)/2])
+	}
+
+	// the first digits are consecutive
+	if len(next) > 1 {
+		return next[:1]
+	}
+	return string(digits[prevDigit]) + midpoint(tail(prev, 1), "")
+}
+
+func increment(prev string) string {
+	for i := 0; i < len(prev); i++ {
+		if index := strings.IndexByte(digits, prev[i]); index < len(digits)-1 {
+			return prev[:i] + string(digits[index+1])
+		}
+	}
+	return prev + string(digits[1])
+}
+
+func decrement(next string) string {
+	for i := 0; i < len(next); i++ {
+		if index := strings.IndexByte(digits, next[i]); index > 1 {
+			return next[:i] + string(digits[index-1])
+		}
+	}
+	// only the digits "0" and "1" are left and the last one is "1"
+	return next[:len(next)-1] + string(digits[0]) + string(digits[len(digits)-1])
+}
+
+func digitAt(rank string, index int) byte {
+	if index < len(rank) {
+		return rank[index]
+	}
+	return digits[0]
+}
+
+func tail(rank string, from int) string {
+	if from >= len(rank) {
+		return ""
+	}
+	return rank[from:]
+}
+
+func isValid(rank string) bool {
+	if strings.HasSuffix(rank, string(digits[0])) {
+		return false
+	}
+
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(digits, rank[i]) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,13 +1,17 @@
 package testhelper
 
 import (
+	"bytes"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"time"
 
 	jwthelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/jwt"
+	storagehelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/storage"
 	"github.com/Manuel-Leleuly/kanban-flow-go/models"
 	"github.com/joho/godotenv"
 	"golang.org/x/crypto/bcrypt"
@@ -36,6 +40,8 @@ func ConnectToTestDB(d *models.DBInstance) error {
 		return err
 	}
 
+	d.Storage = storagehelper.NewLocalStorage(filepath.Join(os.TempDir(), "kanban-flow-go-test-uploads"))
+
 	return nil
 }
 
@@ -90,6 +96,7 @@ func CreateTestUser(d *models.DBInstance) error {
 		return err
 	}
 
+	// the default teams are added with the personal workspace of the user
 	return nil
 }
 
@@ -101,6 +108,64 @@ func DeleteAllTestUsers(d *models.DBInstance) error {
 	return nil
 }
 
+func DeleteAllTestTeams(d *models.DBInstance) error {
+	var teams []models.Team
+	if err := d.DB.Raw("TRUNCATE teams").Scan(&teams).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func DeleteAllTestSavedViews(d *models.DBInstance) error {
+	var views []models.SavedView
+	if err := d.DB.Raw("TRUNCATE saved_views").Scan(&views).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// workspace
+func DeleteAllTestWorkspaces(d *models.DBInstance) error {
+	var workspaces []models.Workspace
+	if err := d.DB.Raw("TRUNCATE workspaces CASCADE").Scan(&workspaces).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// board
+var TEST_BOARD models.Board = models.Board{
+	ID:          "3c5b1e0f9a7d4e2b8f6a1c9d0e7b5a42",
+	Name:        "Test Board",
+	Description: "Test Board Description",
+	CreatedAt:   time.Now(),
+	UpdatedAt:   time.Now(),
+	User:        TEST_USER,
+}
+
+func CreateTestBoard(d *models.DBInstance) error {
+	newBoard := models.Board{
+		ID:          TEST_BOARD.ID,
+		Name:        TEST_BOARD.Name,
+		Description: TEST_BOARD.Description,
+		UserID:      TEST_USER.ID,
+	}
+
+	if err := d.DB.Create(&newBoard).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DeleteAllTestBoards(d *models.DBInstance) error {
+	var boards []models.Board
+	if err := d.DB.Raw("TRUNCATE boards CASCADE").Scan(&boards).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 // ticket
 var TEST_TICKET models.Ticket = models.Ticket{
 	ID:          "7fa00bcc3bc94bada4992d321e94528a",
@@ -111,6 +176,7 @@ var TEST_TICKET models.Ticket = models.Ticket{
 	CreatedAt:   time.Now(),
 	UpdatedAt:   time.Now(),
 	User:        TEST_USER,
+	Board:       TEST_BOARD,
 }
 
 func CreateTestTicket(d *models.DBInstance) error {
@@ -121,6 +187,7 @@ func CreateTestTicket(d *models.DBInstance) error {
 		Assignees:   TEST_TICKET.Assignees,
 		Status:      TEST_TICKET.Status,
 		User:        TEST_USER,
+		BoardID:     TEST_BOARD.ID,
 	}
 
 	if err := d.DB.Create(&newTicket).Error; err != nil {
@@ -132,7 +199,7 @@ func CreateTestTicket(d *models.DBInstance) error {
 
 func DeleteAllTestTickets(d *models.DBInstance) error {
 	var tickets []models.Ticket
-	if err := d.DB.Raw("TRUNCATE tickets").Scan(&tickets).Error; err != nil {
+	if err := d.DB.Raw("TRUNCATE tickets CASCADE").Scan(&tickets).Error; err != nil {
 		return err
 	}
 	return nil
@@ -149,3 +216,23 @@ func GetHTTPRequest(method string, path string, body io.Reader, token string) *h
 
 	return request
 }
+
+// GetMultipartRequest returns a request that uploads the content as a file of a multipart form
+func GetMultipartRequest(path string, field string, filename string, content []byte, token string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, _ := writer.CreateFormFile(field, filename)
+	part.Write(content)
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, path, &body)
+	request.Header.Add("Content-Type", writer.FormDataContentType())
+	request.Header.Add("Accept", "application/json")
+
+	if token != "" {
+		request.Header.Add("Authorization", "Bearer "+token)
+	}
+
+	return request
+}
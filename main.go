@@ -12,6 +12,7 @@ import (
 
 	_ "github.com/Manuel-Leleuly/kanban-flow-go/docs"
 	dbhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/db"
+	storagehelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/storage"
 	"github.com/Manuel-Leleuly/kanban-flow-go/initializer"
 	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
 	"github.com/sirupsen/logrus"
@@ -49,6 +50,12 @@ func main() {
 		logrus.Fatal("[Error] failed to sync database due to: " + err.Error())
 	}
 
+	storage, err := storagehelper.NewStorageFromEnv()
+	if err != nil {
+		logrus.Fatal("[Error] failed to create file storage due to: " + err.Error())
+	}
+	db.Storage = storage
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
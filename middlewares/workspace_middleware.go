@@ -0,0 +1,93 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/context"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// workspaceIDsOf selects the workspaces of the resources that can be referenced in the path of a kanban route
+var workspaceIDsOf = map[string]func(db *gorm.DB, id string) *gorm.DB{
+	"workspaceId": func(db *gorm.DB, id string) *gorm.DB {
+		return db.Model(&models.Workspace{}).Select("workspaces.id").Where("workspaces.id = ?", id)
+	},
+	"boardId": func(db *gorm.DB, id string) *gorm.DB {
+		return db.Model(&models.Board{}).Select("boards.workspace_id").Where("boards.id = ?", id)
+	},
+	// trashed tickets can be restored and purged, keys can match tickets in several workspaces
+	"ticketId": func(db *gorm.DB, id string) *gorm.DB {
+		return db.Model(&models.Ticket{}).Unscoped().Select("boards.workspace_id").
+			Joins("JOIN boards ON boards.id = tickets.board_id").
+			Scopes(models.TicketsByIDOrKey(id))
+	},
+	"labelId": func(db *gorm.DB, id string) *gorm.DB {
+		return db.Model(&models.Label{}).Select("boards.workspace_id").
+			Joins("JOIN boards ON boards.id = labels.board_id").
+			Where("labels.id = ?", id)
+	},
+	"templateId": func(db *gorm.DB, id string) *gorm.DB {
+		return db.Model(&models.TicketTemplate{}).Select("boards.workspace_id").
+			Joins("JOIN boards ON boards.id = ticket_templates.board_id").
+			Where("ticket_templates.id = ?", id)
+	},
+	"teamId": func(db *gorm.DB, id string) *gorm.DB {
+		return db.Model(&models.Team{}).Select("teams.workspace_id").Where("teams.id = ?", id)
+	},
+}
+
+/*
+CheckWorkspaceRole makes sure the user has at least the given role in the
+workspaces of the resources in the path. Resources of workspaces the
+user isn't a member of are left to the controllers, which respond with
+404 as usual.
+Routes without resources in the path only touch the personal workspace of
+the user or are filtered by workspace visibility.
+*/
+func CheckWorkspaceRole(role string) func(d *models.DBInstance, c *gin.Context) {
+	return func(d *models.DBInstance, c *gin.Context) {
+		user, err := context.GetUserFromContext(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorMessage{
+				Message: "unauthorized access",
+			})
+			return
+		}
+
+		for _, param := range c.Params {
+			workspaceIDs, ok := workspaceIDsOf[param.Key]
+			if !ok {
+				continue
+			}
+
+			roles, err := models.GetWorkspaceRoles(d.DB, user.ID, workspaceIDs(d.DB.Session(&gorm.Session{NewDB: true}), param.Value))
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorMessage{
+					Message: "failed to check workspace role",
+				})
+				return
+			}
+
+			// a ticket key that matches tickets in several workspaces of the user doesn't tell which role to check
+			if param.Key == "ticketId" && len(roles) > 1 {
+				c.AbortWithStatusJSON(http.StatusConflict, models.ErrorMessage{
+					Message: models.ErrAmbiguousTicketKey.Error(),
+				})
+				return
+			}
+
+			for _, memberRole := range roles {
+				if !models.HasWorkspaceRole(memberRole, role) {
+					c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorMessage{
+						Message: "requires the " + role + " role in the workspace",
+					})
+					return
+				}
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_ATTACHMENT_MAX_SIZE_MB = 10
+	// how long a download URL of an attachment can be used
+	ATTACHMENT_DOWNLOAD_URL_TTL = 15 * time.Minute
+)
+
+// content types attachments can have, detected from the content of the file
+var ATTACHMENT_CONTENT_TYPES = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+	"application/zip",
+	"application/x-gzip",
+}
+
+// AttachmentMaxSize returns the max size of an attachment in bytes
+func AttachmentMaxSize() int64 {
+	if megabytes, err := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_SIZE_MB"), 10, 64); err == nil && megabytes > 0 {
+		return megabytes << 20
+	}
+	return DEFAULT_ATTACHMENT_MAX_SIZE_MB << 20
+}
+
+// Attachment is a file uploaded to a ticket. The file itself is in the storage under StorageKey.
+type Attachment struct {
+	ID          string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Filename    string    `gorm:"column:filename;not null" json:"filename"`
+	ContentType string    `gorm:"column:content_type;not null" json:"content_type"`
+	Size        int64     `gorm:"column:size;not null" json:"size"`
+	StorageKey  string    `gorm:"column:storage_key;not null;<-create" json:"-"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+
+	// belongs to
+	TicketID string `gorm:"not null;index;<-create" json:"ticket_id"`
+	Ticket   Ticket `json:"ticket"`
+	UserID   string `gorm:"not null" json:"user_id"`
+	User     User   `json:"user"`
+}
+
+func (a *Attachment) TableName() string {
+	return "attachments"
+}
+
+func (a *Attachment) BeforeCreate(db *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+func (a *Attachment) ToAttachmentResponse() AttachmentResponse {
+	return AttachmentResponse{
+		ID:          a.ID,
+		TicketID:    a.TicketID,
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Uploader:    a.User.ToUserResponse(),
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+// ContentDisposition makes browsers download the attachment under its filename
+func (a *Attachment) ContentDisposition() string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})
+}
+
+// AttachmentStorageKey returns where the file of an attachment is stored
+func AttachmentStorageKey(ticketID string, attachmentID string) string {
+	return "tickets/" + ticketID + "/attachments/" + attachmentID
+}
+
+// IsAttachmentContentType reports whether a detected content type like "text/plain; charset=utf-8" is allowed
+func IsAttachmentContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range ATTACHMENT_CONTENT_TYPES {
+		if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// SanitizeAttachmentFilename drops the directories and control characters of an uploaded filename
+func SanitizeAttachmentFilename(filename string) string {
+	filename = filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filename)
+	filename = strings.TrimSpace(filename)
+
+	if filename == "" || filename == "." || filename == "/" {
+		return "file"
+	}
+
+	// keep the extension when the name is too long
+	if runes := []rune(filename); len(runes) > 255 {
+		extension := []rune(filepath.Ext(filename))
+		if len(extension) > 20 {
+			extension = nil
+		}
+		filename = string(runes[:255-len(extension)]) + string(extension)
+	}
+
+	return filename
+}
+
+// response
+type AttachmentResponse struct {
+	ID          string       `json:"id"`
+	TicketID    string       `json:"ticket_id"`
+	Filename    string       `json:"filename"`
+	ContentType string       `json:"content_type"`
+	Size        int64        `json:"size"`
+	Uploader    UserResponse `json:"uploader"`
+	// signed URL that downloads the file without an access token until it expires
+	DownloadURL          string    `json:"download_url"`
+	DownloadURLExpiresAt time.Time `json:"download_url_expires_at"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+type AttachmentDeleteResponse struct {
+	Message string `json:"message"`
+}
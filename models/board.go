@@ -0,0 +1,283 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_BOARD_NAME = "My Board"
+	// key of boards whose name doesn't have enough letters for a key
+	DEFAULT_BOARD_KEY = "KAN"
+)
+
+// board keys are the prefix of ticket keys, e.g. KAN in KAN-42
+var boardKeyPattern = regexp.MustCompile("^[A-Z][A-Z0-9]{1,9}$")
+
+type Board struct {
+	ID          string         `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Name        string         `gorm:"column:name;not null" json:"name"`
+	Description string         `gorm:"column:description" json:"description"`
+	Key         string         `gorm:"column:key;not null;default:'';uniqueIndex:idx_boards_workspace_key,where:key <> ''" json:"key"`
+	TicketSeq   int            `gorm:"column:ticket_seq;not null;default:0;<-:create" json:"-"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at" json:"deleted_at"`
+
+	// belongs to
+	UserID      string    `json:"user_id"`
+	User        User      `json:"user"`
+	WorkspaceID string    `gorm:"uniqueIndex:idx_boards_workspace_key" json:"workspace_id"`
+	Workspace   Workspace `json:"workspace"`
+}
+
+func (b *Board) TableName() string {
+	return "boards"
+}
+
+func (b *Board) BeforeCreate(db *gorm.DB) error {
+	if b.ID == "" {
+		b.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	// boards created without a workspace belong to the personal workspace of their creator
+	if b.WorkspaceID == "" {
+		workspace, err := GetPersonalWorkspace(db.Session(&gorm.Session{NewDB: true}), b.UserID)
+		if err != nil {
+			return err
+		}
+		b.WorkspaceID = workspace.ID
+	}
+	if b.Key == "" {
+		// the workspace stays locked until the board is created, so boards created at the same time don't get the same key
+		tx := db.Session(&gorm.Session{NewDB: true})
+		if err := LockWorkspace(tx, b.WorkspaceID); err != nil {
+			return err
+		}
+
+		key, err := NewBoardKey(tx, b.WorkspaceID, b.Name)
+		if err != nil {
+			return err
+		}
+		b.Key = key
+	}
+	return nil
+}
+
+// every board starts with the default workflow
+func (b *Board) AfterCreate(db *gorm.DB) error {
+	return CreateDefaultBoardColumns(db.Session(&gorm.Session{NewDB: true}), b.ID)
+}
+
+func (b *Board) ToBoardResponse() BoardResponse {
+	return BoardResponse{
+		ID:          b.ID,
+		Name:        b.Name,
+		Description: b.Description,
+		Key:         b.Key,
+		WorkspaceID: b.WorkspaceID,
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+	}
+}
+
+// scopes
+func BoardsVisibleTo(user *User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("boards.workspace_id IN (?)", memberWorkspaces(db, user.ID))
+	}
+}
+
+/*
+GetDefaultBoard returns the oldest board of the personal workspace of
+the user. If the workspace doesn't have any board yet, a new one will be
+created.
+*/
+func GetDefaultBoard(db *gorm.DB, userID string) (*Board, error) {
+	workspace, err := GetPersonalWorkspace(db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var board Board
+	err = db.Where("workspace_id = ?", workspace.ID).Order("created_at ASC").First(&board).Error
+	if err == nil {
+		return &board, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	board = Board{
+		Name:        DEFAULT_BOARD_NAME,
+		UserID:      userID,
+		WorkspaceID: workspace.ID,
+	}
+	if err := db.Create(&board).Error; err != nil {
+		return nil, err
+	}
+
+	return &board, nil
+}
+
+/*
+NewBoardKey derives a key from the name of the board that none of the
+boards of the workspace has yet:
+- the initials of names with several words, e.g. "Kanban Flow" becomes KF
+- the first three letters of names with a single word, e.g. "Kanban" becomes KAN
+- a number is added when the key is taken, e.g. KAN2
+The workspace has to be locked with LockWorkspace until the board is created.
+*/
+func NewBoardKey(db *gorm.DB, workspaceID string, name string) (string, error) {
+	base := boardKeyFromName(name)
+
+	key := base
+	for i := 2; ; i++ {
+		taken, err := IsBoardKeyTaken(db, workspaceID, key)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return key, nil
+		}
+
+		key = base + strconv.Itoa(i)
+	}
+}
+
+// keys of deleted boards stay taken, the keys of their tickets must stay unique
+func IsBoardKeyTaken(db *gorm.DB, workspaceID string, key string) (bool, error) {
+	var count int64
+	if err := db.Model(&Board{}).Unscoped().Where("workspace_id = ? AND key = ?", workspaceID, key).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func boardKeyFromName(name string) string {
+	words := strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return !('A' <= r && r <= 'Z') && !('0' <= r && r <= '9')
+	})
+
+	// keys start with a letter
+	for len(words) > 0 && !('A' <= words[0][0] && words[0][0] <= 'Z') {
+		words = words[1:]
+	}
+
+	var key string
+	switch {
+	case len(words) == 0:
+		return DEFAULT_BOARD_KEY
+	case len(words) == 1:
+		key = words[0][:min(3, len(words[0]))]
+	default:
+		for _, word := range words[:min(4, len(words))] {
+			key += word[:1]
+		}
+	}
+
+	if len(key) < 2 {
+		return DEFAULT_BOARD_KEY
+	}
+	return key
+}
+
+// request body
+type BoardCreateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// prefix of the ticket keys, derived from the name when omitted
+	Key string `json:"key"`
+}
+
+func (bcr BoardCreateRequest) Validate() error {
+	return validation.ValidateStruct(
+		&bcr,
+		/*
+			Name validations:
+			- is required
+			- min length 1
+			- max length 50
+		*/
+		validation.Field(
+			&bcr.Name,
+			validation.Required.Error("is required"),
+			validation.Length(1, 50).Error("must have length between 1 and 50"),
+		),
+
+		/*
+			Description validations:
+			- min length 1
+			- max length 200
+		*/
+		validation.Field(
+			&bcr.Description,
+			validation.Length(1, 200).Error("must have length between 1 and 200"),
+		),
+
+		/*
+			Key validations:
+			- is optional
+			- starts with an uppercase letter
+			- only contains uppercase letters and numbers
+			- length between 2 and 10
+		*/
+		validation.Field(
+			&bcr.Key,
+			validation.Match(boardKeyPattern).Error("must have length between 2 and 10, start with an uppercase letter and only contain uppercase letters and numbers"),
+		),
+	)
+}
+
+type BoardUpdateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (bur BoardUpdateRequest) Validate() error {
+	return validation.ValidateStruct(
+		&bur,
+		/*
+			Name validations:
+			- is required
+			- min length 1
+			- max length 50
+		*/
+		validation.Field(
+			&bur.Name,
+			validation.Required.Error("is required"),
+			validation.Length(1, 50).Error("must have length between 1 and 50"),
+		),
+
+		/*
+			Description validations:
+			- min length 1
+			- max length 200
+		*/
+		validation.Field(
+			&bur.Description,
+			validation.Length(1, 200).Error("must have length between 1 and 200"),
+		),
+	)
+}
+
+// response
+type BoardResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Key         string    `json:"key"`
+	WorkspaceID string    `json:"workspace_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type BoardDeleteResponse struct {
+	Message string `json:"message"`
+}
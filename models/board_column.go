@@ -0,0 +1,232 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+// columns every new board starts with
+var DEFAULT_BOARD_COLUMNS []BoardColumn = []BoardColumn{
+	{Key: "todo", Name: "To Do", Color: "#94a3b8"},
+	{Key: "doing", Name: "Doing", Color: "#3b82f6"},
+	{Key: "done", Name: "Done", Color: "#22c55e"},
+}
+
+// WIPLimit is the max number of tickets in the column, there is no limit when it is nil
+type BoardColumn struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Key       string    `gorm:"column:key;not null;uniqueIndex:idx_board_columns_board_key" json:"key"`
+	Name      string    `gorm:"column:name;not null" json:"name"`
+	Color     string    `gorm:"column:color;not null" json:"color"`
+	Position  int       `gorm:"column:position;not null" json:"position"`
+	WIPLimit  *int      `gorm:"column:wip_limit" json:"wip_limit"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+
+	// belongs to
+	BoardID string `gorm:"uniqueIndex:idx_board_columns_board_key" json:"board_id"`
+	Board   Board  `json:"board"`
+}
+
+func (bc *BoardColumn) TableName() string {
+	return "board_columns"
+}
+
+func (bc *BoardColumn) BeforeCreate(db *gorm.DB) error {
+	if bc.ID == "" {
+		bc.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+func (bc *BoardColumn) ToBoardColumnResponse() BoardColumnResponse {
+	return BoardColumnResponse{
+		Key:      bc.Key,
+		Name:     bc.Name,
+		Color:    bc.Color,
+		Position: bc.Position,
+		WIPLimit: bc.WIPLimit,
+	}
+}
+
+// HasReachedWIPLimit reports whether another ticket would exceed the WIP limit of the column
+func (bc *BoardColumn) HasReachedWIPLimit(ticketCount int64) bool {
+	return bc.WIPLimit != nil && ticketCount >= int64(*bc.WIPLimit)
+}
+
+func CreateDefaultBoardColumns(db *gorm.DB, boardID string) error {
+	columns := make([]BoardColumn, len(DEFAULT_BOARD_COLUMNS))
+	for i, column := range DEFAULT_BOARD_COLUMNS {
+		columns[i] = BoardColumn{
+			Key:      column.Key,
+			Name:     column.Name,
+			Color:    column.Color,
+			Position: i,
+			BoardID:  boardID,
+		}
+	}
+
+	return db.Create(&columns).Error
+}
+
+// GetBoardColumns returns the workflow of the board ordered by position
+func GetBoardColumns(db *gorm.DB, boardID string) ([]BoardColumn, error) {
+	var columns []BoardColumn
+	if err := db.Where("board_id = ?", boardID).Order("position ASC").Find(&columns).Error; err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// GetColumnTicketCounts returns the number of tickets in each column of the board
+func GetColumnTicketCounts(db *gorm.DB, boardID string) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := db.Model(&Ticket{}).
+		Select("status, COUNT(*) AS count").
+		Where("board_id = ?", boardID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func BoardColumnKeys(columns []BoardColumn) []string {
+	keys := make([]string, len(columns))
+	for i, column := range columns {
+		keys[i] = column.Key
+	}
+	return keys
+}
+
+// request body
+type BoardColumnRequest struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	WIPLimit *int   `json:"wip_limit"`
+}
+
+func (bcr BoardColumnRequest) Validate() error {
+	return validation.ValidateStruct(
+		&bcr,
+		/*
+			Key validations:
+			- is required
+			- starts with a lowercase letter
+			- only contains lowercase letters, numbers, "-" and "_"
+			- max length 30
+		*/
+		validation.Field(
+			&bcr.Key,
+			validation.Required.Error("is required"),
+			validation.Length(1, 30).Error("must have length between 1 and 30"),
+			validation.Match(regexp.MustCompile("^[a-z][a-z0-9_-]*$")).Error("must start with a lowercase letter and only contain lowercase letters, numbers, \"-\" or \"_\""),
+		),
+
+		/*
+			Name validations:
+			- is required
+			- min length 1
+			- max length 30
+		*/
+		validation.Field(
+			&bcr.Name,
+			validation.Required.Error("is required"),
+			validation.Length(1, 30).Error("must have length between 1 and 30"),
+		),
+
+		/*
+			Color validations:
+			- is required
+			- must be a hex color (e.g. #22c55e)
+		*/
+		validation.Field(
+			&bcr.Color,
+			validation.Required.Error("is required"),
+			validation.Match(regexp.MustCompile("^#[0-9a-fA-F]{6}$")).Error("must be a hex color such as #22c55e"),
+		),
+
+		/*
+			WIPLimit validations:
+			- is optional (no limit)
+			- min 1
+			- max 1000
+		*/
+		validation.Field(
+			&bcr.WIPLimit,
+			validation.Min(1).Error("must be at least 1"),
+			validation.Max(1000).Error("must be at most 1000"),
+		),
+	)
+}
+
+type BoardColumnsUpdateRequest struct {
+	Columns []BoardColumnRequest `json:"columns"`
+}
+
+func (bcur BoardColumnsUpdateRequest) Validate() error {
+	return validation.ValidateStruct(
+		&bcur,
+		/*
+			Columns validations:
+			- is required
+			- min 1 column
+			- max 20 columns
+			- keys must not contain duplicates
+		*/
+		validation.Field(
+			&bcur.Columns,
+			validation.Required.Error("is required"),
+			validation.Length(1, 20).Error("must have between 1 and 20 columns"),
+			validation.By(func(value interface{}) error {
+				seen := make(map[string]bool, len(bcur.Columns))
+				for _, column := range bcur.Columns {
+					if seen[column.Key] {
+						return errors.New("contains duplicate key " + column.Key)
+					}
+					seen[column.Key] = true
+				}
+				return nil
+			}),
+		),
+	)
+}
+
+// response
+type BoardColumnResponse struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	Position int    `json:"position"`
+	WIPLimit *int   `json:"wip_limit"`
+}
+
+// number of tickets in a column next to its WIP limit
+type ColumnWIPResponse struct {
+	Key      string `json:"key"`
+	WIPLimit *int   `json:"wip_limit"`
+	Count    int64  `json:"count"`
+}
+
+type WIPLimitErrorMessage struct {
+	Message  string `json:"message"`
+	Status   string `json:"status"`
+	WIPLimit int    `json:"wip_limit"`
+	Count    int64  `json:"count"`
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+// ChecklistItem is a step of a ticket. Items are ordered by their rank.
+type ChecklistItem struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Text      string    `gorm:"column:text;type:text;not null" json:"text"`
+	Done      bool      `gorm:"column:done;not null;default:false" json:"done"`
+	Assignee  string    `gorm:"column:assignee;not null;default:''" json:"assignee"`
+	Rank      string    `gorm:"column:rank;type:text COLLATE \"C\";not null" json:"rank"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+
+	// belongs to
+	TicketID string `gorm:"not null;index;<-create" json:"ticket_id"`
+	Ticket   Ticket `json:"ticket"`
+}
+
+func (ci *ChecklistItem) TableName() string {
+	return "checklist_items"
+}
+
+func (ci *ChecklistItem) BeforeCreate(db *gorm.DB) error {
+	if ci.ID == "" {
+		ci.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+func (ci *ChecklistItem) ToChecklistItemResponse() ChecklistItemResponse {
+	return ChecklistItemResponse{
+		ID:        ci.ID,
+		TicketID:  ci.TicketID,
+		Text:      ci.Text,
+		Done:      ci.Done,
+		Assignee:  ci.Assignee,
+		Rank:      ci.Rank,
+		CreatedAt: ci.CreatedAt,
+		UpdatedAt: ci.UpdatedAt,
+	}
+}
+
+// scopes
+func OrderChecklistItems(db *gorm.DB) *gorm.DB {
+	return db.Order("checklist_items.rank ASC")
+}
+
+// request body
+type ChecklistItemRequest struct {
+	Text     string `json:"text"`
+	Done     bool   `json:"done"`
+	Assignee string `json:"assignee"`
+}
+
+// teams are the teams the ticket can be assigned to
+func (cir ChecklistItemRequest) Validate(teams []string) error {
+	return validation.ValidateStruct(
+		&cir,
+		/*
+			Text validations:
+			- is required
+			- min length 1
+			- max length 500
+		*/
+		validation.Field(
+			&cir.Text,
+			validation.Required.Error("is required"),
+			validation.Length(1, 500).Error("must have length between 1 and 500"),
+		),
+
+		/*
+			Assignee validations:
+			- only allows the teams of the board owner
+		*/
+		validation.Field(
+			&cir.Assignee,
+			validation.In(toInterfaceSlice(teams)...).Error(allowedValuesMessage(teams)),
+		),
+	)
+}
+
+// the item is placed between its new neighbours
+type ChecklistItemMoveRequest struct {
+	BeforeID string `json:"before_id"`
+	AfterID  string `json:"after_id"`
+}
+
+func (cimr ChecklistItemMoveRequest) Validate() error {
+	return validation.ValidateStruct(
+		&cimr,
+		/*
+			AfterID validations:
+			- must be different from BeforeID
+		*/
+		validation.Field(
+			&cimr.AfterID,
+			validation.NotIn(cimr.BeforeID).Error("must be different from before_id"),
+		),
+	)
+}
+
+// response
+type ChecklistItemResponse struct {
+	ID        string    `json:"id"`
+	TicketID  string    `json:"ticket_id"`
+	Text      string    `json:"text"`
+	Done      bool      `json:"done"`
+	Assignee  string    `json:"assignee"`
+	Rank      string    `json:"rank"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ChecklistItemDeleteResponse struct {
+	Message string `json:"message"`
+}
+
+// ChecklistProgress tells how much of the checklist of a ticket is done
+type ChecklistProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+	// between 0 and 1, 0 for tickets without a checklist
+	Ratio float64 `json:"ratio"`
+}
+
+func NewChecklistProgress(done int, total int) ChecklistProgress {
+	progress := ChecklistProgress{
+		Done:  done,
+		Total: total,
+	}
+	if total > 0 {
+		progress.Ratio = float64(done) / float64(total)
+	}
+	return progress
+}
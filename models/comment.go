@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+/*
+Comment is a message on a ticket. A comment without a parent starts a
+thread, a comment with a parent is a reply to that thread. Replies can't
+be replied to, so threads are only one level deep.
+*/
+type Comment struct {
+	ID        string         `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Body      string         `gorm:"column:body;type:text;not null" json:"body"`
+	EditedAt  *time.Time     `gorm:"column:edited_at" json:"edited_at"`
+	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at" json:"deleted_at"`
+
+	// belongs to
+	TicketID string  `gorm:"not null;index" json:"ticket_id"`
+	Ticket   Ticket  `json:"ticket"`
+	UserID   string  `gorm:"not null" json:"user_id"`
+	User     User    `json:"user"`
+	ParentID *string `gorm:"column:parent_id;index;<-create" json:"parent_id"`
+
+	// has many
+	Replies []Comment `gorm:"foreignKey:ParentID" json:"replies"`
+}
+
+func (c *Comment) TableName() string {
+	return "comments"
+}
+
+func (c *Comment) BeforeCreate(db *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+func (c *Comment) ToCommentResponse() CommentResponse {
+	response := CommentResponse{
+		ID:        c.ID,
+		TicketID:  c.TicketID,
+		ParentID:  c.ParentID,
+		Body:      c.Body,
+		Author:    c.User.ToUserResponse(),
+		EditedAt:  c.EditedAt,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+
+	// only threads have replies
+	if c.ParentID == nil {
+		response.Replies = []CommentResponse{}
+		for _, reply := range c.Replies {
+			response.Replies = append(response.Replies, reply.ToCommentResponse())
+		}
+	}
+
+	return response
+}
+
+// request body
+type CommentCreateRequest struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parent_id"`
+}
+
+func (ccr CommentCreateRequest) Validate() error {
+	return validation.ValidateStruct(
+		&ccr,
+		/*
+			Body validations:
+			- is required
+			- min length 1
+			- max length 5000
+		*/
+		validation.Field(
+			&ccr.Body,
+			validation.Required.Error("is required"),
+			validation.Length(1, 5000).Error("must have length between 1 and 5000"),
+		),
+	)
+}
+
+type CommentUpdateRequest struct {
+	Body string `json:"body"`
+}
+
+func (cur CommentUpdateRequest) Validate() error {
+	return validation.ValidateStruct(
+		&cur,
+		/*
+			Body validations:
+			- is required
+			- min length 1
+			- max length 5000
+		*/
+		validation.Field(
+			&cur.Body,
+			validation.Required.Error("is required"),
+			validation.Length(1, 5000).Error("must have length between 1 and 5000"),
+		),
+	)
+}
+
+// response
+type CommentResponse struct {
+	ID        string            `json:"id"`
+	TicketID  string            `json:"ticket_id"`
+	ParentID  *string           `json:"parent_id"`
+	Body      string            `json:"body"`
+	Author    UserResponse      `json:"author"`
+	EditedAt  *time.Time        `json:"edited_at"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Replies   []CommentResponse `json:"replies,omitempty"`
+}
+
+type CommentDeleteResponse struct {
+	Message string `json:"message"`
+}
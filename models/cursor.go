@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+/*
+Cursor points to the last item of a page. The next page starts right
+after the item with the given sort value and ID. Clients receive it as
+an opaque string.
+*/
+type Cursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, errors.New("invalid cursor")
+	}
+
+	return &cursor, nil
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	rankhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/rank"
+	storagehelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/storage"
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -17,6 +19,9 @@ import (
 
 type DBInstance struct {
 	*gorm.DB
+
+	// where the files of the attachments are stored
+	Storage storagehelper.Storage
 }
 
 func (d *DBInstance) ConnectToDB(dbName string) error {
@@ -69,7 +74,54 @@ func (d *DBInstance) SyncDatabase() error {
 		return errors.New("DB is not initialized")
 	}
 
-	d.DB.AutoMigrate(&User{}, &Ticket{})
+	hasTeamWorkspaces := d.DB.Migrator().HasColumn(&Team{}, "workspace_id")
+	hasPersonalWorkspaces := d.DB.Migrator().HasColumn(&Workspace{}, "personal_user_id")
+
+	d.DB.AutoMigrate(&User{}, &Team{}, &Workspace{}, &Membership{}, &Invitation{}, &Board{}, &BoardColumn{}, &Label{}, &Ticket{}, &TicketEvent{}, &Comment{}, &Mention{}, &Notification{}, &ChecklistItem{}, &TicketLink{}, &Attachment{}, &SavedView{}, &TicketTemplate{})
+
+	if err := d.createSearchVectors(); err != nil {
+		return err
+	}
+
+	if err := d.assignUsernames(); err != nil {
+		return err
+	}
+
+	if err := d.createMissingBoardColumns(); err != nil {
+		return err
+	}
+
+	if !hasPersonalWorkspaces {
+		if err := d.assignPersonalWorkspaces(); err != nil {
+			return err
+		}
+	}
+
+	if !hasTeamWorkspaces {
+		if err := d.assignTeamsToWorkspaces(); err != nil {
+			return err
+		}
+	}
+
+	if err := d.assignBoardsToPersonalWorkspaces(); err != nil {
+		return err
+	}
+
+	if err := d.assignTicketsToDefaultBoards(); err != nil {
+		return err
+	}
+
+	if err := d.assignBoardKeys(); err != nil {
+		return err
+	}
+
+	if err := d.assignTicketKeys(); err != nil {
+		return err
+	}
+
+	if err := d.rankUnrankedTickets(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -90,6 +142,272 @@ func (d *DBInstance) MakeHTTPHandleFunc(f func(db *DBInstance, c *gin.Context))
 	}
 }
 
+// users created before usernames existed get a username derived from their email
+func (d *DBInstance) assignUsernames() error {
+	var users []User
+	if err := d.DB.Unscoped().Where("username = ''").Order("created_at").Find(&users).Error; err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		username, err := NewUsername(d.DB, user.Email)
+		if err != nil {
+			return err
+		}
+
+		if err := d.DB.Model(&User{}).Unscoped().Where("id = ?", user.ID).UpdateColumn("username", username).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// boards created before workflows existed get the default columns
+func (d *DBInstance) createMissingBoardColumns() error {
+	var boardIDs []string
+	columnBoards := d.DB.Model(&BoardColumn{}).Select("board_id")
+	if err := d.DB.Model(&Board{}).Unscoped().Where("id NOT IN (?)", columnBoards).Pluck("id", &boardIDs).Error; err != nil {
+		return err
+	}
+
+	for _, boardID := range boardIDs {
+		if err := CreateDefaultBoardColumns(d.DB, boardID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+before personal workspaces were marked, the oldest workspace a user owned
+was their personal workspace. Every user keeps the oldest workspace they
+created themselves, so no workspace is the personal workspace of two users.
+*/
+func (d *DBInstance) assignPersonalWorkspaces() error {
+	return d.DB.Exec(`
+		UPDATE workspaces SET personal_user_id = personal.user_id
+		FROM (
+			SELECT DISTINCT ON (creators.user_id) creators.user_id, creators.workspace_id
+			FROM (
+				SELECT DISTINCT ON (workspace_id) workspace_id, user_id, created_at
+				FROM memberships
+				WHERE role = ?
+				ORDER BY workspace_id, created_at ASC
+			) AS creators
+			ORDER BY creators.user_id, creators.created_at ASC
+		) AS personal
+		WHERE workspaces.id = personal.workspace_id`,
+		WORKSPACE_ROLE_OWNER,
+	).Error
+}
+
+/*
+teams created before teams belonged to workspaces move to the personal
+workspace of the user who created them, unless it already has a team with
+the same key. Workspaces without teams get the default teams once.
+*/
+func (d *DBInstance) assignTeamsToWorkspaces() error {
+	// keys are unique per workspace now
+	if d.DB.Migrator().HasIndex(&Team{}, "idx_teams_user_key") {
+		if err := d.DB.Migrator().DropIndex(&Team{}, "idx_teams_user_key"); err != nil {
+			return err
+		}
+	}
+
+	var userIDs []string
+	if err := d.DB.Model(&Team{}).Where("workspace_id IS NULL").Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		workspace, err := GetPersonalWorkspace(d.DB, userID)
+		if err != nil {
+			return err
+		}
+
+		workspaceKeys := d.DB.Model(&Team{}).Select("key").Where("workspace_id = ?", workspace.ID)
+		if err := d.DB.Model(&Team{}).Where("workspace_id IS NULL AND user_id = ? AND key NOT IN (?)", userID, workspaceKeys).UpdateColumn("workspace_id", workspace.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	// the rest are duplicates of the default teams of new personal workspaces
+	if err := d.DB.Where("workspace_id IS NULL").Delete(&Team{}).Error; err != nil {
+		return err
+	}
+
+	var workspaces []Workspace
+	teamWorkspaces := d.DB.Model(&Team{}).Select("workspace_id")
+	if err := d.DB.Where("id NOT IN (?)", teamWorkspaces).Preload("Memberships", "role = ?", WORKSPACE_ROLE_OWNER).Find(&workspaces).Error; err != nil {
+		return err
+	}
+
+	// workspaces always keep an owner
+	for _, workspace := range workspaces {
+		if len(workspace.Memberships) == 0 {
+			continue
+		}
+
+		if err := CreateDefaultTeams(d.DB, workspace.ID, workspace.Memberships[0].UserID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+boards created before workspaces existed move to the personal workspace
+of the user who created them. Their keys were unique per user, so they
+stay unique in the workspace.
+*/
+func (d *DBInstance) assignBoardsToPersonalWorkspaces() error {
+	// keys are unique per workspace now
+	if d.DB.Migrator().HasIndex(&Board{}, "idx_boards_user_key") {
+		if err := d.DB.Migrator().DropIndex(&Board{}, "idx_boards_user_key"); err != nil {
+			return err
+		}
+	}
+
+	var userIDs []string
+	if err := d.DB.Model(&Board{}).Unscoped().Where("workspace_id IS NULL").Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		workspace, err := GetPersonalWorkspace(d.DB, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := d.DB.Model(&Board{}).Unscoped().Where("workspace_id IS NULL AND user_id = ?", userID).UpdateColumn("workspace_id", workspace.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+tickets created before boards existed don't have a board yet.
+Move them to the default board of the user who created them.
+*/
+func (d *DBInstance) assignTicketsToDefaultBoards() error {
+	var userIDs []string
+	if err := d.DB.Model(&Ticket{}).Unscoped().Where("board_id IS NULL").Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		board, err := GetDefaultBoard(d.DB, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := d.DB.Model(&Ticket{}).Unscoped().Where("board_id IS NULL AND user_id = ?", userID).Update("board_id", board.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// boards created before keys existed get a key derived from their name
+func (d *DBInstance) assignBoardKeys() error {
+	var boards []Board
+	if err := d.DB.Unscoped().Where("key = ''").Order("created_at").Find(&boards).Error; err != nil {
+		return err
+	}
+
+	for _, board := range boards {
+		err := d.DB.Transaction(func(tx *gorm.DB) error {
+			if err := LockWorkspace(tx, board.WorkspaceID); err != nil {
+				return err
+			}
+
+			key, err := NewBoardKey(tx, board.WorkspaceID, board.Name)
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&Board{}).Unscoped().Where("id = ?", board.ID).UpdateColumn("key", key).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tickets created before keys existed are numbered in the order they were created
+func (d *DBInstance) assignTicketKeys() error {
+	var tickets []Ticket
+	if err := d.DB.Unscoped().Where("key = ''").Order("created_at, id").Find(&tickets).Error; err != nil {
+		return err
+	}
+
+	for _, ticket := range tickets {
+		err := d.DB.Transaction(func(tx *gorm.DB) error {
+			key, err := NextTicketKey(tx, ticket.BoardID)
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&Ticket{}).Unscoped().Where("id = ?", ticket.ID).UpdateColumn("key", key).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+tickets created before ranks existed are placed at the bottom of
+their column, ordered by their creation time.
+*/
+func (d *DBInstance) rankUnrankedTickets() error {
+	var tickets []Ticket
+	if err := d.DB.Unscoped().Where("rank = ''").Order("board_id, status, created_at").Find(&tickets).Error; err != nil {
+		return err
+	}
+
+	lastRanks := make(map[string]string)
+	for _, ticket := range tickets {
+		column := ticket.BoardID + "/" + ticket.Status
+
+		prevRank, ok := lastRanks[column]
+		if !ok {
+			var ranks []string
+			err := d.DB.Model(&Ticket{}).Unscoped().
+				Where("board_id = ? AND status = ? AND rank <> ''", ticket.BoardID, ticket.Status).
+				Order("rank DESC").Limit(1).Pluck("rank", &ranks).Error
+			if err != nil {
+				return err
+			}
+			if len(ranks) > 0 {
+				prevRank = ranks[0]
+			}
+		}
+
+		rank, err := rankhelper.After(prevRank)
+		if err != nil {
+			return err
+		}
+
+		if err := d.DB.Model(&Ticket{}).Unscoped().Where("id = ?", ticket.ID).UpdateColumn("rank", rank).Error; err != nil {
+			return err
+		}
+		lastRanks[column] = rank
+	}
+
+	return nil
+}
+
 // helpers
 func getGORMDatabaseUrl(dbName string, params map[string]string) string {
 	dbUrl := fmt.Sprintf("postgresql://%s:%s@%s/%s", os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_DOMAIN"), dbName)
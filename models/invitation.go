@@ -0,0 +1,189 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"gorm.io/gorm"
+)
+
+// how long the token of an invitation can be used, resending the invitation starts a new period
+const INVITATION_TTL = 7 * 24 * time.Hour
+
+/*
+Invitation invites an email address to join a workspace with a role. The
+token is only handed out when the invitation is created or resent, only
+its hash is stored.
+*/
+type Invitation struct {
+	ID         string     `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Email      string     `gorm:"column:email;not null" json:"email"`
+	Role       string     `gorm:"column:role;not null" json:"role"`
+	TokenHash  string     `gorm:"column:token_hash;not null;uniqueIndex" json:"-"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	AcceptedAt *time.Time `gorm:"column:accepted_at" json:"accepted_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+
+	// belongs to
+	WorkspaceID string    `gorm:"not null;index;<-create" json:"workspace_id"`
+	Workspace   Workspace `json:"workspace"`
+	InvitedByID string    `gorm:"not null;<-create" json:"invited_by_id"`
+	InvitedBy   User      `gorm:"foreignKey:InvitedByID" json:"invited_by"`
+}
+
+func (i *Invitation) TableName() string {
+	return "invitations"
+}
+
+func (i *Invitation) BeforeCreate(db *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+// RenewToken replaces the token of the invitation and returns the new token, the old token can't be used anymore
+func (i *Invitation) RenewToken(now time.Time) (string, error) {
+	token, err := helpers.GenerateSecretToken()
+	if err != nil {
+		return "", err
+	}
+
+	i.TokenHash = hashInvitationToken(token)
+	i.ExpiresAt = now.Add(INVITATION_TTL)
+
+	return token, nil
+}
+
+func (i *Invitation) IsExpired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// the token is only part of the response when the invitation is created or resent
+func (i *Invitation) ToInvitationResponse(token string) InvitationResponse {
+	return InvitationResponse{
+		ID:          i.ID,
+		WorkspaceID: i.WorkspaceID,
+		Email:       i.Email,
+		Role:        i.Role,
+		InvitedByID: i.InvitedByID,
+		Token:       token,
+		Expired:     i.IsExpired(time.Now()),
+		ExpiresAt:   i.ExpiresAt,
+		CreatedAt:   i.CreatedAt,
+		UpdatedAt:   i.UpdatedAt,
+	}
+}
+
+// scopes
+
+// invitations that haven't been accepted yet, expired invitations can still be resent
+func InvitationsPending(db *gorm.DB) *gorm.DB {
+	return db.Where("invitations.accepted_at IS NULL")
+}
+
+// invitations of the workspaces the user is an admin or owner of
+func InvitationsManagedBy(user *User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		managedWorkspaces := db.Session(&gorm.Session{NewDB: true}).Model(&Membership{}).Select("workspace_id").
+			Where("user_id = ? AND role IN ?", user.ID, []string{WORKSPACE_ROLE_OWNER, WORKSPACE_ROLE_ADMIN})
+		return db.Where("invitations.workspace_id IN (?)", managedWorkspaces)
+	}
+}
+
+// InvitationsByToken finds the invitation of a token that can still be accepted
+func InvitationsByToken(token string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(InvitationsPending).
+			Where("invitations.token_hash = ? AND invitations.expires_at > ?", hashInvitationToken(token), time.Now())
+	}
+}
+
+func hashInvitationToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// request body
+type InvitationCreateRequest struct {
+	WorkspaceID string `json:"workspace_id"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+}
+
+func (icr InvitationCreateRequest) Validate() error {
+	return validation.ValidateStruct(
+		&icr,
+		/*
+			WorkspaceID validations:
+			- is required
+		*/
+		validation.Field(
+			&icr.WorkspaceID,
+			validation.Required.Error("is required"),
+		),
+
+		/*
+			Email validations:
+			- is required
+			- must be in email format
+		*/
+		validation.Field(
+			&icr.Email,
+			validation.Required.Error("is required"),
+			is.Email.Error("must be in email format"),
+		),
+
+		/*
+			Role validations:
+			- is required
+			- only allows owner, admin, member, viewer
+		*/
+		validation.Field(
+			&icr.Role,
+			validation.Required.Error("is required"),
+			validation.In(toInterfaceSlice(WORKSPACE_ROLES)...).Error(allowedValuesMessage(WORKSPACE_ROLES)),
+		),
+	)
+}
+
+type InvitationAcceptRequest struct {
+	Token string `json:"token"`
+}
+
+func (iar InvitationAcceptRequest) Validate() error {
+	return validation.ValidateStruct(
+		&iar,
+		/*
+			Token validations:
+			- is required
+		*/
+		validation.Field(
+			&iar.Token,
+			validation.Required.Error("is required"),
+		),
+	)
+}
+
+// response
+type InvitationResponse struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	InvitedByID string    `json:"invited_by_id"`
+	Token       string    `json:"token,omitempty"`
+	Expired     bool      `json:"expired"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type InvitationDeleteResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// JSONValue is any JSON value stored in a jsonb column
+type JSONValue json.RawMessage
+
+func NewJSONValue(value any) (JSONValue, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return JSONValue(bytes), nil
+}
+
+func (jv JSONValue) Value() (driver.Value, error) {
+	if len(jv) == 0 {
+		return nil, nil
+	}
+
+	return []byte(jv), nil
+}
+
+func (jv *JSONValue) Scan(src any) error {
+	if src == nil {
+		*jv = nil
+		return nil
+	}
+
+	bytes, ok := src.([]byte)
+	if !ok {
+		return errors.New("[Error] src cannot be cast to []byte")
+	}
+
+	*jv = append((*jv)[0:0], bytes...)
+	return nil
+}
+
+func (jv JSONValue) MarshalJSON() ([]byte, error) {
+	if len(jv) == 0 {
+		return []byte("null"), nil
+	}
+
+	return []byte(jv), nil
+}
+
+func (jv *JSONValue) UnmarshalJSON(data []byte) error {
+	*jv = append((*jv)[0:0], data...)
+	return nil
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+type Label struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Name      string    `gorm:"column:name;not null;uniqueIndex:idx_labels_board_name" json:"name"`
+	Color     string    `gorm:"column:color;not null" json:"color"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+
+	// belongs to
+	BoardID string `gorm:"not null;uniqueIndex:idx_labels_board_name;<-create" json:"board_id"`
+	Board   Board  `json:"board"`
+}
+
+func (l *Label) TableName() string {
+	return "labels"
+}
+
+func (l *Label) BeforeCreate(db *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+func (l *Label) ToLabelResponse() LabelResponse {
+	return LabelResponse{
+		ID:        l.ID,
+		BoardID:   l.BoardID,
+		Name:      l.Name,
+		Color:     l.Color,
+		CreatedAt: l.CreatedAt,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
+
+// scopes
+func LabelsVisibleTo(user *User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		visibleBoards := db.Session(&gorm.Session{NewDB: true}).Model(&Board{}).Select("boards.id").Scopes(BoardsVisibleTo(user))
+		return db.Where("labels.board_id IN (?)", visibleBoards)
+	}
+}
+
+// labels of a ticket are always listed by name
+func OrderLabels(db *gorm.DB) *gorm.DB {
+	return db.Order("labels.name ASC")
+}
+
+// request body
+type LabelRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func (lr LabelRequest) Validate() error {
+	return validation.ValidateStruct(
+		&lr,
+		/*
+			Name validations:
+			- is required
+			- min length 1
+			- max length 30
+		*/
+		validation.Field(
+			&lr.Name,
+			validation.Required.Error("is required"),
+			validation.Length(1, 30).Error("must have length between 1 and 30"),
+		),
+
+		/*
+			Color validations:
+			- is required
+			- must be a hex color (e.g. #22c55e)
+		*/
+		validation.Field(
+			&lr.Color,
+			validation.Required.Error("is required"),
+			validation.Match(regexp.MustCompile("^#[0-9a-fA-F]{6}$")).Error("must be a hex color such as #22c55e"),
+		),
+	)
+}
+
+// response
+type LabelResponse struct {
+	ID        string    `json:"id"`
+	BoardID   string    `json:"board_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type LabelDeleteResponse struct {
+	Message string `json:"message"`
+}
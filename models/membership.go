@@ -0,0 +1,211 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"gorm.io/gorm"
+)
+
+/*
+roles of the members of a workspace, every role can do everything the
+roles below it can do:
+- owner: manages the owners of the workspace
+- admin: manages the members, the settings of the workspace and the settings of its boards
+- member: works on the tickets of the boards
+- viewer: only reads the boards
+*/
+const (
+	WORKSPACE_ROLE_OWNER  = "owner"
+	WORKSPACE_ROLE_ADMIN  = "admin"
+	WORKSPACE_ROLE_MEMBER = "member"
+	WORKSPACE_ROLE_VIEWER = "viewer"
+)
+
+var WORKSPACE_ROLES = []string{WORKSPACE_ROLE_OWNER, WORKSPACE_ROLE_ADMIN, WORKSPACE_ROLE_MEMBER, WORKSPACE_ROLE_VIEWER}
+
+var workspaceRoleRanks = map[string]int{
+	WORKSPACE_ROLE_VIEWER: 1,
+	WORKSPACE_ROLE_MEMBER: 2,
+	WORKSPACE_ROLE_ADMIN:  3,
+	WORKSPACE_ROLE_OWNER:  4,
+}
+
+// Membership gives a user a role in a workspace
+type Membership struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Role      string    `gorm:"column:role;not null" json:"role"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+
+	// belongs to
+	WorkspaceID string    `gorm:"not null;uniqueIndex:idx_memberships_workspace_user;<-create" json:"workspace_id"`
+	Workspace   Workspace `json:"workspace"`
+	UserID      string    `gorm:"not null;uniqueIndex:idx_memberships_workspace_user;index;<-create" json:"user_id"`
+	User        User      `json:"user"`
+}
+
+func (m *Membership) TableName() string {
+	return "memberships"
+}
+
+func (m *Membership) BeforeCreate(db *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+// the user of the membership has to be loaded
+func (m *Membership) ToMembershipResponse() MembershipResponse {
+	return MembershipResponse{
+		ID:          m.ID,
+		WorkspaceID: m.WorkspaceID,
+		Role:        m.Role,
+		User:        m.User.ToUserResponse(),
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// HasWorkspaceRole tells whether the role allows everything the required role allows
+func HasWorkspaceRole(role string, requiredRole string) bool {
+	rank, ok := workspaceRoleRanks[role]
+	return ok && rank >= workspaceRoleRanks[requiredRole]
+}
+
+// GetWorkspaceRole returns the role of the user in the workspace, gorm.ErrRecordNotFound when the user isn't a member
+func GetWorkspaceRole(db *gorm.DB, userID string, workspaceID string) (string, error) {
+	var membership Membership
+	if err := db.Where("user_id = ? AND workspace_id = ?", userID, workspaceID).First(&membership).Error; err != nil {
+		return "", err
+	}
+
+	return membership.Role, nil
+}
+
+// GetWorkspaceRoles returns the roles of the user in the workspaces selected by the subquery
+func GetWorkspaceRoles(db *gorm.DB, userID string, workspaceIDs *gorm.DB) ([]string, error) {
+	var roles []string
+	if err := db.Model(&Membership{}).Where("user_id = ? AND workspace_id IN (?)", userID, workspaceIDs).Pluck("role", &roles).Error; err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// CountWorkspaceOwners is used to make sure a workspace always keeps an owner
+func CountWorkspaceOwners(db *gorm.DB, workspaceID string) (int64, error) {
+	var count int64
+	if err := db.Model(&Membership{}).Where("workspace_id = ? AND role = ?", workspaceID, WORKSPACE_ROLE_OWNER).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetWorkspaceMemberIDs returns the IDs of all members of the workspace
+func GetWorkspaceMemberIDs(db *gorm.DB, workspaceID string) ([]string, error) {
+	var ids []string
+	if err := db.Model(&Membership{}).Where("workspace_id = ?", workspaceID).Pluck("user_id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetBoardMemberIDs returns the IDs of all members of the workspace of the board
+func GetBoardMemberIDs(db *gorm.DB, boardID string) ([]string, error) {
+	var ids []string
+	err := db.Model(&Membership{}).
+		Joins("JOIN boards ON boards.workspace_id = memberships.workspace_id").
+		Where("boards.id = ?", boardID).
+		Pluck("memberships.user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// UnassignWorkspaceTickets removes the user from the assignees of all tickets of the workspace, e.g. when the user leaves it
+func UnassignWorkspaceTickets(db *gorm.DB, userID string, workspaceID string) error {
+	return db.Exec(
+		"DELETE FROM ticket_assignees WHERE ticket_assignees.user_id = ? AND ticket_assignees.ticket_id IN (SELECT tickets.id FROM tickets JOIN boards ON boards.id = tickets.board_id WHERE boards.workspace_id = ?)",
+		userID, workspaceID,
+	).Error
+}
+
+// the workspaces the user is a member of
+func memberWorkspaces(db *gorm.DB, userID string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(&Membership{}).Select("memberships.workspace_id").Where("memberships.user_id = ?", userID)
+}
+
+// request body
+type MembershipCreateRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+func (mcr MembershipCreateRequest) Validate() error {
+	return validation.ValidateStruct(
+		&mcr,
+		/*
+			Email validations:
+			- is required
+			- must be in email format
+		*/
+		validation.Field(
+			&mcr.Email,
+			validation.Required.Error("is required"),
+			is.Email.Error("must be in email format"),
+		),
+
+		/*
+			Role validations:
+			- is required
+			- only allows owner, admin, member, viewer
+		*/
+		validation.Field(
+			&mcr.Role,
+			validation.Required.Error("is required"),
+			validation.In(toInterfaceSlice(WORKSPACE_ROLES)...).Error(allowedValuesMessage(WORKSPACE_ROLES)),
+		),
+	)
+}
+
+type MembershipUpdateRequest struct {
+	Role string `json:"role"`
+}
+
+func (mur MembershipUpdateRequest) Validate() error {
+	return validation.ValidateStruct(
+		&mur,
+		/*
+			Role validations:
+			- is required
+			- only allows owner, admin, member, viewer
+		*/
+		validation.Field(
+			&mur.Role,
+			validation.Required.Error("is required"),
+			validation.In(toInterfaceSlice(WORKSPACE_ROLES)...).Error(allowedValuesMessage(WORKSPACE_ROLES)),
+		),
+	)
+}
+
+// response
+type MembershipResponse struct {
+	ID          string       `json:"id"`
+	WorkspaceID string       `json:"workspace_id"`
+	Role        string       `json:"role"`
+	User        UserResponse `json:"user"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type MembershipDeleteResponse struct {
+	Message string `json:"message"`
+}
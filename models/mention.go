@@ -0,0 +1,106 @@
+package models
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	"gorm.io/gorm"
+)
+
+/*
+users are mentioned by their email or their username, e.g. "thanks
+@jane@example.com" or "thanks @jane_doe". Emails are tried first, so a
+username is never the start of an email.
+*/
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9._%+-])@([A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+|[A-Za-z0-9_]+)`)
+
+/*
+Mention records that a user was mentioned in the description of a ticket
+or in a comment on it. Mentions in the description don't have a comment.
+*/
+type Mention struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+
+	// belongs to
+	TicketID  string   `gorm:"not null;index;<-create" json:"ticket_id"`
+	Ticket    Ticket   `json:"ticket"`
+	CommentID *string  `gorm:"index;<-create" json:"comment_id"`
+	Comment   *Comment `json:"comment"`
+	UserID    string   `gorm:"not null;index;<-create" json:"user_id"`
+	User      User     `json:"user"`
+	ActorID   string   `gorm:"not null;<-create" json:"actor_id"`
+	Actor     User     `gorm:"foreignKey:ActorID" json:"actor"`
+}
+
+func (m *Mention) TableName() string {
+	return "mentions"
+}
+
+func (m *Mention) BeforeCreate(db *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+/*
+MentionedHandles returns the lowercased emails and usernames mentioned in
+the text, each handle once. Handles with an @ are emails.
+*/
+func MentionedHandles(text string) []string {
+	handles := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		handle := strings.ToLower(text[match[2]:match[3]])
+
+		// an email without a domain isn't a username either
+		isEmail := strings.Contains(handle, "@")
+		if !isEmail && (!usernamePattern.MatchString(handle) || strings.HasPrefix(text[match[3]:], "@")) {
+			continue
+		}
+
+		if !slices.Contains(handles, handle) {
+			handles = append(handles, handle)
+		}
+	}
+	return handles
+}
+
+// NewMentionedHandles returns the handles mentioned in the new version of a text but not in the old one
+func NewMentionedHandles(oldText string, newText string) []string {
+	oldHandles := MentionedHandles(oldText)
+
+	handles := []string{}
+	for _, handle := range MentionedHandles(newText) {
+		if !slices.Contains(oldHandles, handle) {
+			handles = append(handles, handle)
+		}
+	}
+	return handles
+}
+
+// IsMentionedBy reports whether the handle is the email or the username of the user
+func (u *User) IsMentionedBy(handle string) bool {
+	return strings.EqualFold(u.Email, handle) || u.Username == handle
+}
+
+// GetMentionableUsers returns the users of the handles that can see the tickets of the board
+func GetMentionableUsers(db *gorm.DB, boardID string, handles []string) ([]User, error) {
+	users := []User{}
+	if len(handles) == 0 {
+		return users, nil
+	}
+
+	members := db.Session(&gorm.Session{NewDB: true}).Model(&Membership{}).Select("memberships.user_id").
+		Joins("JOIN boards ON boards.workspace_id = memberships.workspace_id").
+		Where("boards.id = ?", boardID)
+
+	if err := db.Where("(LOWER(users.email) IN ? OR users.username IN ?) AND users.id IN (?)", handles, handles, members).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
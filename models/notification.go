@@ -0,0 +1,179 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	NOTIFICATION_TYPE_ASSIGNED       = "assigned"
+	NOTIFICATION_TYPE_UNASSIGNED     = "unassigned"
+	NOTIFICATION_TYPE_STATUS_CHANGED = "status_changed"
+	NOTIFICATION_TYPE_MENTIONED      = "mentioned"
+)
+
+const (
+	DEFAULT_NOTIFICATION_LIST_LIMIT = 50
+	MAX_NOTIFICATION_LIST_LIMIT     = 100
+)
+
+/*
+Notification tells a user that someone else did something on a ticket that
+concerns them, e.g. assigned them to it or mentioned them in a comment.
+Notifications of mentions in comments also carry the comment.
+*/
+type Notification struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Type      string    `gorm:"column:type;not null;<-create" json:"type"`
+	Read      bool      `gorm:"column:read;not null;default:false" json:"read"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create;index:idx_notifications_user_created,priority:2" json:"created_at"`
+
+	// belongs to
+	UserID    string   `gorm:"not null;<-create;index:idx_notifications_user_created,priority:1" json:"user_id"`
+	User      User     `json:"user"`
+	ActorID   string   `gorm:"not null;<-create" json:"actor_id"`
+	Actor     User     `gorm:"foreignKey:ActorID" json:"actor"`
+	TicketID  string   `gorm:"not null;index;<-create" json:"ticket_id"`
+	Ticket    Ticket   `json:"ticket"`
+	CommentID *string  `gorm:"<-create" json:"comment_id"`
+	Comment   *Comment `json:"comment"`
+}
+
+func (n *Notification) TableName() string {
+	return "notifications"
+}
+
+func (n *Notification) BeforeCreate(db *gorm.DB) error {
+	if n.ID == "" {
+		n.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+// the actor and the ticket have to be loaded
+func (n *Notification) ToNotificationResponse() NotificationResponse {
+	return NotificationResponse{
+		ID:   n.ID,
+		Type: n.Type,
+		Read: n.Read,
+		Ticket: NotificationTicketResponse{
+			ID:      n.Ticket.ID,
+			Key:     n.Ticket.Key,
+			BoardID: n.Ticket.BoardID,
+			Title:   n.Ticket.Title,
+			Status:  n.Ticket.Status,
+		},
+		CommentID: n.CommentID,
+		Actor:     n.Actor.ToUserResponse(),
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+// scopes
+
+// notifications of the user about tickets the user can still see, tickets in the trash included
+func NotificationsOf(user *User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		visibleTickets := db.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&Ticket{}).Select("tickets.id").Scopes(TicketsVisibleTo(user))
+		return db.Where("notifications.user_id = ? AND notifications.ticket_id IN (?)", user.ID, visibleTickets)
+	}
+}
+
+func NotificationsUnread(db *gorm.DB) *gorm.DB {
+	return db.Where("notifications.read = ?", false)
+}
+
+// query params
+type NotificationListQuery struct {
+	Unread bool   `form:"unread"`
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
+
+func (nlq NotificationListQuery) Validate() error {
+	return validation.ValidateStruct(
+		&nlq,
+		/*
+			Limit validations:
+			- min 1
+			- max 100
+		*/
+		validation.Field(
+			&nlq.Limit,
+			validation.Min(1).Error("must be at least 1"),
+			validation.Max(MAX_NOTIFICATION_LIST_LIMIT).Error(fmt.Sprintf("must be at most %d", MAX_NOTIFICATION_LIST_LIMIT)),
+		),
+
+		/*
+			Cursor validations:
+			- must be a cursor returned by a previous page
+		*/
+		validation.Field(
+			&nlq.Cursor,
+			validation.By(func(value interface{}) error {
+				if nlq.Cursor == "" {
+					return nil
+				}
+				_, err := DecodeCursor(nlq.Cursor)
+				return err
+			}),
+		),
+	)
+}
+
+func (nlq NotificationListQuery) WithDefaults() NotificationListQuery {
+	if nlq.Limit == 0 {
+		nlq.Limit = DEFAULT_NOTIFICATION_LIST_LIMIT
+	}
+	return nlq
+}
+
+/*
+Page returns the newest notifications first and only returns the
+notifications after the cursor. One more notification than the limit is
+returned so the caller knows whether there is a next page.
+*/
+func (nlq NotificationListQuery) Page(cursor *Cursor) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if cursor != nil {
+			db = db.Where("(notifications.created_at, notifications.id) < (?, ?)", cursor.Value, cursor.ID)
+		}
+
+		return db.Order("notifications.created_at DESC, notifications.id DESC").Limit(nlq.Limit + 1)
+	}
+}
+
+// response
+type NotificationTicketResponse struct {
+	ID      string `json:"id"`
+	Key     string `json:"key"`
+	BoardID string `json:"board_id"`
+	Title   string `json:"title"`
+	Status  string `json:"status"`
+}
+
+type NotificationResponse struct {
+	ID        string                     `json:"id"`
+	Type      string                     `json:"type" enums:"assigned,unassigned,status_changed,mentioned"`
+	Read      bool                       `json:"read"`
+	Ticket    NotificationTicketResponse `json:"ticket"`
+	CommentID *string                    `json:"comment_id"`
+	Actor     UserResponse               `json:"actor"`
+	CreatedAt time.Time                  `json:"created_at"`
+}
+
+type NotificationListResponse struct {
+	Data        []NotificationResponse `json:"data"`
+	UnreadCount int64                  `json:"unread_count"`
+	NextCursor  string                 `json:"next_cursor"`
+}
+
+type NotificationReadAllResponse struct {
+	Message string `json:"message"`
+	// number of notifications that were unread
+	Updated int64 `json:"updated"`
+}
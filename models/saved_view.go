@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+// a named query of the ticket query language
+type SavedView struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Name      string    `gorm:"column:name;not null;uniqueIndex:idx_saved_views_user_name" json:"name"`
+	Query     string    `gorm:"column:query;type:text;not null" json:"query"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+
+	// belongs to
+	UserID string `gorm:"not null;uniqueIndex:idx_saved_views_user_name;<-create" json:"user_id"`
+	User   User   `json:"user"`
+}
+
+func (sv *SavedView) TableName() string {
+	return "saved_views"
+}
+
+func (sv *SavedView) BeforeCreate(db *gorm.DB) error {
+	if sv.ID == "" {
+		sv.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+func (sv *SavedView) ToSavedViewResponse() SavedViewResponse {
+	return SavedViewResponse{
+		ID:        sv.ID,
+		Name:      sv.Name,
+		Query:     sv.Query,
+		CreatedAt: sv.CreatedAt,
+		UpdatedAt: sv.UpdatedAt,
+	}
+}
+
+// scopes
+func SavedViewsVisibleTo(user *User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("saved_views.user_id = ?", user.ID)
+	}
+}
+
+// request body
+type SavedViewRequest struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+func (svr SavedViewRequest) Validate() error {
+	return validation.ValidateStruct(
+		&svr,
+		/*
+			Name validations:
+			- is required
+			- min length 1
+			- max length 50
+		*/
+		validation.Field(
+			&svr.Name,
+			validation.Required.Error("is required"),
+			validation.Length(1, 50).Error("must have length between 1 and 50"),
+		),
+
+		/*
+			Query validations:
+			- is required
+			- max length 500
+			- must be a valid query of the ticket query language
+		*/
+		validation.Field(
+			&svr.Query,
+			validation.Required.Error("is required"),
+			validation.Length(1, 500).Error("must have length between 1 and 500"),
+			validation.By(func(value interface{}) error {
+				_, err := TicketQuery(svr.Query, nil, time.Now())
+				return err
+			}),
+		),
+	)
+}
+
+// response
+type SavedViewResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SavedViewDeleteResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_SEARCH_LANGUAGE = "english"
+	DEFAULT_SEARCH_LIMIT    = 20
+	MAX_SEARCH_LIMIT        = 100
+)
+
+// SearchLanguage returns the text search configuration used for stemming
+func SearchLanguage() string {
+	if language := os.Getenv("SEARCH_LANGUAGE"); language != "" {
+		return strings.ToLower(language)
+	}
+	return DEFAULT_SEARCH_LANGUAGE
+}
+
+/*
+search vectors are generated by postgres from the columns of their row.
+Matches in the title rank higher than matches in the description.
+*/
+var searchVectorExpressions = map[string]string{
+	"tickets":  "setweight(to_tsvector('%[1]s'::regconfig, coalesce(title, '')), 'A') || setweight(to_tsvector('%[1]s'::regconfig, coalesce(description, '')), 'B')",
+	"comments": "to_tsvector('%[1]s'::regconfig, coalesce(body, ''))",
+}
+
+/*
+createSearchVectors adds the generated search_vector columns and their GIN
+indexes. gorm can't migrate generated columns, and the language of a
+generated column can't change, so the columns are created again when
+SEARCH_LANGUAGE changes.
+*/
+func (d *DBInstance) createSearchVectors() error {
+	language := SearchLanguage()
+
+	var languageCount int64
+	if err := d.DB.Raw("SELECT COUNT(*) FROM pg_ts_config WHERE cfgname = ?", language).Scan(&languageCount).Error; err != nil {
+		return err
+	}
+	if languageCount == 0 {
+		return fmt.Errorf("unknown search language %q", language)
+	}
+
+	for table, expression := range searchVectorExpressions {
+		var currentExpressions []string
+		err := d.DB.Raw(
+			"SELECT generation_expression FROM information_schema.columns WHERE table_name = ? AND column_name = 'search_vector'",
+			table,
+		).Scan(&currentExpressions).Error
+		if err != nil {
+			return err
+		}
+
+		if len(currentExpressions) > 0 {
+			if strings.Contains(currentExpressions[0], "'"+language+"'::regconfig") {
+				continue
+			}
+
+			// the index is dropped with the column
+			if err := d.DB.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN search_vector", table)).Error; err != nil {
+				return err
+			}
+		}
+
+		statements := []string{
+			fmt.Sprintf("ALTER TABLE %s ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (%s) STORED", table, fmt.Sprintf(expression, language)),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%[1]s_search_vector ON %[1]s USING GIN (search_vector)", table),
+		}
+		for _, statement := range statements {
+			if err := d.DB.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+escapeHTML HTML-escapes a text expression in SQL. The text is escaped
+before it's highlighted, so the <mark> tags are the only markup in the
+highlights.
+*/
+func escapeHTML(expression string) string {
+	return fmt.Sprintf(`replace(replace(replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`, expression)
+}
+
+// scopes
+/*
+TicketSearch selects the tickets that match the search terms themselves or
+through one of their comments, best matches first. Only the best matching
+comment of a ticket is used for its rank and its highlight.
+*/
+func TicketSearch(terms string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		language := SearchLanguage()
+		titleOptions := "HighlightAll=true, StartSel=<mark>, StopSel=</mark>"
+		textOptions := "MaxFragments=2, MaxWords=20, MinWords=5, StartSel=<mark>, StopSel=</mark>"
+
+		return db.
+			Select(
+				"tickets.id AS ticket_id, "+
+					"ts_rank(tickets.search_vector, search_query) + COALESCE(comment_hit.score, 0) AS score, "+
+					"ts_headline(?::regconfig, "+escapeHTML("tickets.title")+", search_query, ?) AS title, "+
+					"CASE WHEN tickets.search_vector @@ search_query THEN ts_headline(?::regconfig, "+escapeHTML("tickets.description")+", search_query, ?) ELSE '' END AS description, "+
+					"COALESCE(ts_headline(?::regconfig, "+escapeHTML("comment_hit.body")+", search_query, ?), '') AS comment",
+				language, titleOptions, language, textOptions, language, textOptions,
+			).
+			Joins("CROSS JOIN websearch_to_tsquery(?::regconfig, ?) AS search_query", language, terms).
+			Joins(
+				"LEFT JOIN LATERAL (?) AS comment_hit ON true",
+				db.Session(&gorm.Session{NewDB: true}).Model(&Comment{}).
+					Select("comments.body, ts_rank(comments.search_vector, search_query) AS score").
+					Where("comments.ticket_id = tickets.id AND comments.search_vector @@ search_query").
+					Order("score DESC").Limit(1),
+			).
+			Where("tickets.search_vector @@ search_query OR comment_hit.body IS NOT NULL").
+			Order("score DESC, tickets.id ASC")
+	}
+}
+
+// query params
+type SearchQuery struct {
+	Q       string `form:"q"`
+	BoardID string `form:"board_id"`
+	Limit   int    `form:"limit"`
+}
+
+func (sq SearchQuery) Validate() error {
+	return validation.ValidateStruct(
+		&sq,
+		/*
+			Q validations:
+			- is required
+			- max length 200
+		*/
+		validation.Field(
+			&sq.Q,
+			validation.Required.Error("is required"),
+			validation.Length(1, 200).Error("must have length between 1 and 200"),
+		),
+
+		/*
+			Limit validations:
+			- min 1
+			- max 100
+		*/
+		validation.Field(
+			&sq.Limit,
+			validation.Min(1).Error("must be at least 1"),
+			validation.Max(MAX_SEARCH_LIMIT).Error(fmt.Sprintf("must be at most %d", MAX_SEARCH_LIMIT)),
+		),
+	)
+}
+
+func (sq SearchQuery) WithDefaults() SearchQuery {
+	if sq.Limit == 0 {
+		sq.Limit = DEFAULT_SEARCH_LIMIT
+	}
+	return sq
+}
+
+// a row selected by TicketSearch
+type TicketSearchHit struct {
+	TicketID    string
+	Score       float64
+	Title       string
+	Description string
+	Comment     string
+}
+
+// response
+// matches in the highlights are wrapped in <mark></mark>, the rest of the text is HTML-escaped
+type SearchHighlights struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+type SearchResult struct {
+	Ticket     TicketResponse   `json:"ticket"`
+	Score      float64          `json:"score"`
+	Highlights SearchHighlights `json:"highlights"`
+}
+
+type SearchResponse struct {
+	Data []SearchResult `json:"data"`
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+// teams every new workspace starts with
+var DEFAULT_TEAMS []Team = []Team{
+	{Key: "frontend", Name: "Frontend"},
+	{Key: "backend", Name: "Backend"},
+	{Key: "design", Name: "Design"},
+}
+
+type Team struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Key       string    `gorm:"column:key;not null;uniqueIndex:idx_teams_workspace_key;<-create" json:"key"`
+	Name      string    `gorm:"column:name;not null" json:"name"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+
+	// belongs to
+	WorkspaceID string    `gorm:"uniqueIndex:idx_teams_workspace_key" json:"workspace_id"`
+	Workspace   Workspace `json:"workspace"`
+	UserID      string    `json:"user_id"`
+	User        User      `json:"user"`
+}
+
+func (t *Team) TableName() string {
+	return "teams"
+}
+
+func (t *Team) BeforeCreate(db *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+func (t *Team) ToTeamResponse() TeamResponse {
+	return TeamResponse{
+		ID:          t.ID,
+		Key:         t.Key,
+		Name:        t.Name,
+		WorkspaceID: t.WorkspaceID,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+// scopes
+func TeamsVisibleTo(user *User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("teams.workspace_id IN (?)", memberWorkspaces(db, user.ID))
+	}
+}
+
+// CreateDefaultTeams adds the default teams to the workspace, the user is who created the workspace
+func CreateDefaultTeams(db *gorm.DB, workspaceID string, userID string) error {
+	teams := make([]Team, len(DEFAULT_TEAMS))
+	for i, team := range DEFAULT_TEAMS {
+		teams[i] = Team{
+			Key:         team.Key,
+			Name:        team.Name,
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+		}
+	}
+
+	return db.Create(&teams).Error
+}
+
+// GetTeamKeys returns the keys of the teams tickets of the workspace can be assigned to
+func GetTeamKeys(db *gorm.DB, workspaceID string) ([]string, error) {
+	var keys []string
+	if err := db.Model(&Team{}).Where("workspace_id = ?", workspaceID).Order("created_at ASC").Pluck("key", &keys).Error; err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// request body
+type TeamCreateRequest struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+func (tcr TeamCreateRequest) Validate() error {
+	return validation.ValidateStruct(
+		&tcr,
+		/*
+			Key validations:
+			- is required
+			- starts with a lowercase letter
+			- only contains lowercase letters, numbers, "-" and "_"
+			- max length 30
+		*/
+		validation.Field(
+			&tcr.Key,
+			validation.Required.Error("is required"),
+			validation.Length(1, 30).Error("must have length between 1 and 30"),
+			validation.Match(regexp.MustCompile("^[a-z][a-z0-9_-]*$")).Error("must start with a lowercase letter and only contain lowercase letters, numbers, \"-\" or \"_\""),
+		),
+
+		/*
+			Name validations:
+			- is required
+			- min length 1
+			- max length 50
+		*/
+		validation.Field(
+			&tcr.Name,
+			validation.Required.Error("is required"),
+			validation.Length(1, 50).Error("must have length between 1 and 50"),
+		),
+	)
+}
+
+type TeamUpdateRequest struct {
+	Name string `json:"name"`
+}
+
+func (tur TeamUpdateRequest) Validate() error {
+	return validation.ValidateStruct(
+		&tur,
+		/*
+			Name validations:
+			- is required
+			- min length 1
+			- max length 50
+		*/
+		validation.Field(
+			&tur.Name,
+			validation.Required.Error("is required"),
+			validation.Length(1, 50).Error("must have length between 1 and 50"),
+		),
+	)
+}
+
+// response
+type TeamResponse struct {
+	ID          string    `json:"id"`
+	Key         string    `json:"key"`
+	Name        string    `json:"name"`
+	WorkspaceID string    `json:"workspace_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type TeamDeleteResponse struct {
+	Message string `json:"message"`
+}
@@ -1,6 +1,11 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
@@ -8,19 +13,51 @@ import (
 	"gorm.io/gorm"
 )
 
+// ticket keys are the key of the board and the number of the ticket on that board, e.g. KAN-42
+var ticketKeyPattern = regexp.MustCompile("^[A-Za-z][A-Za-z0-9]{1,9}-[0-9]+$")
+
+/*
+board keys are only unique within a workspace, so a key can match tickets
+in several workspaces of the user. Such keys are rejected instead of
+picking one of the tickets.
+*/
+var ErrAmbiguousTicketKey = errors.New("the ticket key matches tickets in several workspaces, use the ID of the ticket instead")
+
 type Ticket struct {
 	ID          string         `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Key         string         `gorm:"column:key;not null;default:'';uniqueIndex:idx_tickets_key_board,where:key <> ''" json:"key"`
 	Title       string         `gorm:"column:title;not null;" json:"title"`
 	Description string         `gorm:"column:description;" json:"description"`
 	Assignees   StringArray    `gorm:"column:assignees;type:jsonb" json:"assignees"`
 	Status      string         `gorm:"column:status;not null;" json:"status"`
+	DueAt       *time.Time     `gorm:"column:due_at" json:"due_at"`
+	Priority    string         `gorm:"column:priority;not null;default:''" json:"priority"`
+	Estimate    *int           `gorm:"column:estimate" json:"estimate"`
+	Rank        string         `gorm:"column:rank;type:text COLLATE \"C\";not null;default:''" json:"rank"`
+	Version     int            `gorm:"column:version;not null;default:1" json:"version"`
 	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
 	UpdatedAt   time.Time      `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at" json:"deleted_at"`
 
 	// belongs to
-	UserID string `json:"user_id"`
-	User   User   `json:"user"`
+	UserID  string `json:"user_id"`
+	User    User   `json:"user"`
+	BoardID string `gorm:"uniqueIndex:idx_tickets_key_board" json:"board_id"`
+	Board   Board  `json:"board"`
+
+	// many to many
+	Labels        []Label `gorm:"many2many:ticket_labels" json:"labels"`
+	AssigneeUsers []User  `gorm:"many2many:ticket_assignees" json:"assignee_users"`
+
+	// read only, selected by TicketsWithDetails
+	ChecklistDone  int `gorm:"->;-:migration" json:"-"`
+	ChecklistTotal int `gorm:"->;-:migration" json:"-"`
+
+	// read only, selected for linked tickets by TicketLinksOf
+	Done bool `gorm:"->;-:migration" json:"-"`
+
+	// not stored, the notifications caused by the last write
+	Notifications []Notification `gorm:"-" json:"-"`
 }
 
 func (t *Ticket) TableName() string {
@@ -31,30 +68,208 @@ func (t *Ticket) BeforeCreate(db *gorm.DB) error {
 	if t.ID == "" {
 		t.ID = helpers.GenerateUUIDWithoutHyphen()
 	}
+	if t.Version == 0 {
+		t.Version = 1
+	}
+	if t.Key == "" && t.BoardID != "" {
+		key, err := NextTicketKey(db.Session(&gorm.Session{NewDB: true}), t.BoardID)
+		if err != nil {
+			return err
+		}
+		t.Key = key
+	}
 	return nil
 }
 
+/*
+NextTicketKey counts up the ticket sequence of the board and returns the
+key of the next ticket. The board stays locked until the transaction
+ends, so two tickets never get the same number.
+*/
+func NextTicketKey(db *gorm.DB, boardID string) (string, error) {
+	var boardKey string
+	var number int
+	err := db.Raw("UPDATE boards SET ticket_seq = ticket_seq + 1 WHERE id = ? RETURNING key, ticket_seq", boardID).
+		Row().
+		Scan(&boardKey, &number)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-%d", boardKey, number), nil
+}
+
+// IsTicketKey reports whether a ticket is referenced by its key instead of its ID
+func IsTicketKey(value string) bool {
+	return ticketKeyPattern.MatchString(value)
+}
+
+// ETag identifies the version of the ticket for conditional requests
+func (t *Ticket) ETag() string {
+	return fmt.Sprintf("%q", strconv.Itoa(t.Version))
+}
+
+// scopes
+func TicketsVisibleTo(user *User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		visibleBoards := db.Session(&gorm.Session{NewDB: true}).Model(&Board{}).Select("boards.id").Scopes(BoardsVisibleTo(user))
+		return db.Where("tickets.board_id IN (?)", visibleBoards)
+	}
+}
+
+// TicketsByIDOrKey finds a ticket by its ID or by its key, keys are case insensitive and can match several tickets
+func TicketsByIDOrKey(idOrKey string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if IsTicketKey(idOrKey) {
+			return db.Where("tickets.key = ?", strings.ToUpper(idOrKey))
+		}
+		return db.Where("tickets.id = ?", idOrKey)
+	}
+}
+
+// TicketsWithDetails loads everything a ticket response shows besides the columns of the ticket
+func TicketsWithDetails(db *gorm.DB) *gorm.DB {
+	checklist := "SELECT COUNT(*) FROM checklist_items WHERE checklist_items.ticket_id = tickets.id"
+	return db.
+		Select("tickets.*, ("+checklist+") AS checklist_total, ("+checklist+" AND checklist_items.done) AS checklist_done").
+		Preload("Labels", OrderLabels).
+		Preload("AssigneeUsers", OrderUsers)
+}
+
+// tickets in the trash
+func TicketsTrashed(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("tickets.deleted_at IS NOT NULL")
+}
+
+// tickets that are past their due date and not in the last column of their board yet
+func TicketsOverdue(db *gorm.DB) *gorm.DB {
+	return db.Where(ticketsOverdueCondition, time.Now())
+}
+
+const (
+	// tickets are done once they reach the last column of their board
+	ticketsDoneCondition    = "tickets.status = (SELECT board_columns.key FROM board_columns WHERE board_columns.board_id = tickets.board_id ORDER BY board_columns.position DESC LIMIT 1)"
+	ticketsOverdueCondition = "tickets.due_at < ? AND NOT (" + ticketsDoneCondition + ")"
+)
+
 func (t *Ticket) ToTicketResponse() TicketResponse {
-	return TicketResponse{
-		ID:          t.ID,
+	response := TicketResponse{
+		ID:            t.ID,
+		Key:           t.Key,
+		BoardID:       t.BoardID,
+		Title:         t.Title,
+		Description:   t.Description,
+		Assignees:     t.Assignees,
+		AssigneeUsers: t.assigneeUserResponses(),
+		Status:        t.Status,
+		DueAt:         t.DueAt,
+		Priority:      t.Priority,
+		Estimate:      t.Estimate,
+		Rank:          t.Rank,
+		Version:       t.Version,
+		Labels:        t.labelResponses(),
+		Checklist:     NewChecklistProgress(t.ChecklistDone, t.ChecklistTotal),
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
+	}
+
+	if t.DeletedAt.Valid {
+		response.DeletedAt = &t.DeletedAt.Time
+	}
+
+	return response
+}
+
+func (t *Ticket) labelResponses() []LabelResponse {
+	result := []LabelResponse{}
+	for _, label := range t.Labels {
+		result = append(result, label.ToLabelResponse())
+	}
+	return result
+}
+
+func (t *Ticket) assigneeUserResponses() []UserResponse {
+	result := []UserResponse{}
+	for _, user := range t.AssigneeUsers {
+		result = append(result, user.ToUserResponse())
+	}
+	return result
+}
+
+// AssigneeIDs returns the IDs of the users the ticket is assigned to
+func (t *Ticket) AssigneeIDs() []string {
+	ids := []string{}
+	for _, user := range t.AssigneeUsers {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+// LabelNames returns the names of the labels of the ticket
+func (t *Ticket) LabelNames() []string {
+	names := []string{}
+	for _, label := range t.Labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+// ToTicketUpdateRequest returns the current values of the ticket a patch is applied to
+func (t *Ticket) ToTicketUpdateRequest() TicketUpdateRequest {
+	return TicketUpdateRequest{
 		Title:       t.Title,
 		Description: t.Description,
 		Assignees:   t.Assignees,
+		AssigneeIDs: t.AssigneeIDs(),
 		Status:      t.Status,
-		CreatedAt:   t.CreatedAt,
-		UpdatedAt:   t.UpdatedAt,
+		DueAt:       t.DueAt,
+		Priority:    t.Priority,
+		Estimate:    t.Estimate,
 	}
 }
 
+// priorities of a ticket from the lowest to the highest
+var TICKET_PRIORITIES []string = []string{"low", "medium", "high", "urgent"}
+
+// story points
+const MAX_TICKET_ESTIMATE = 100
+
+// PrioritiesFrom returns the given priority and all priorities above it
+func PrioritiesFrom(priority string) []string {
+	for i, p := range TICKET_PRIORITIES {
+		if p == priority {
+			return TICKET_PRIORITIES[i:]
+		}
+	}
+	return []string{}
+}
+
+/*
+TicketRules contains the values a ticket is validated against.
+They depend on the board the ticket belongs to.
+*/
+type TicketRules struct {
+	Statuses []string
+	Teams    []string
+	// IDs of the members of the workspace of the board, only they can see the ticket
+	Members []string
+}
+
 // request body
 type TicketCreateRequest struct {
 	Title       string      `json:"title"`
 	Description string      `json:"description"`
 	Assignees   StringArray `json:"assignees"`
+	AssigneeIDs StringArray `json:"assignee_ids"`
 	Status      string      `json:"status"`
+	DueAt       *time.Time  `json:"due_at"`
+	Priority    string      `json:"priority"`
+	Estimate    *int        `json:"estimate"`
+	// template of the board that pre-fills the fields left out and adds its checklist
+	TemplateID string `json:"template_id"`
 }
 
-func (tcr TicketCreateRequest) Validate() error {
+func (tcr TicketCreateRequest) Validate(rules TicketRules) error {
 	return validation.ValidateStruct(
 		&tcr,
 		/*
@@ -81,24 +296,57 @@ func (tcr TicketCreateRequest) Validate() error {
 
 		/*
 			Assignees validations:
-			- only allows frontend, backend, and ui
+			- only allows the keys of the available teams
 			- must not contain duplicates
 		*/
 		validation.Field(
 			&tcr.Assignees,
 			validation.Each(
-				validation.In("frontend", "backend", "design").Error("only allows \"frontend\", \"backend\", or \"design\""),
+				validation.In(toInterfaceSlice(rules.Teams)...).Error(allowedValuesMessage(rules.Teams)),
 			),
 			tcr.Assignees.ValidateUniqueItems(),
 		),
 
+		/*
+			AssigneeIDs validations:
+			- only allows members of the workspace of the board
+			- must not contain duplicates
+		*/
+		validation.Field(
+			&tcr.AssigneeIDs,
+			validation.Each(
+				validation.In(toInterfaceSlice(rules.Members)...).Error("must be a member of the workspace of the board"),
+			),
+			tcr.AssigneeIDs.ValidateUniqueItems(),
+		),
+
 		/*
 			Status validations:
-			- only allows todo, doing, done
+			- only allows the column keys of the board workflow
 		*/
 		validation.Field(
 			&tcr.Status,
-			validation.In("todo", "doing", "done").Error("only allows \"todo\", \"doing\", or \"done\""),
+			validation.In(toInterfaceSlice(rules.Statuses)...).Error(allowedValuesMessage(rules.Statuses)),
+		),
+
+		/*
+			Priority validations:
+			- only allows low, medium, high, urgent
+		*/
+		validation.Field(
+			&tcr.Priority,
+			validation.In(toInterfaceSlice(TICKET_PRIORITIES)...).Error(allowedValuesMessage(TICKET_PRIORITIES)),
+		),
+
+		/*
+			Estimate validations:
+			- min 0
+			- max 100
+		*/
+		validation.Field(
+			&tcr.Estimate,
+			validation.Min(0).Error("must be at least 0"),
+			validation.Max(MAX_TICKET_ESTIMATE).Error(fmt.Sprintf("must be at most %d", MAX_TICKET_ESTIMATE)),
 		),
 	)
 }
@@ -107,10 +355,14 @@ type TicketUpdateRequest struct {
 	Title       string      `json:"title"`
 	Description string      `json:"description"`
 	Assignees   StringArray `json:"assignees"`
+	AssigneeIDs StringArray `json:"assignee_ids"`
 	Status      string      `json:"status"`
+	DueAt       *time.Time  `json:"due_at"`
+	Priority    string      `json:"priority"`
+	Estimate    *int        `json:"estimate"`
 }
 
-func (tur TicketUpdateRequest) Validate() error {
+func (tur TicketUpdateRequest) Validate(rules TicketRules) error {
 	return validation.ValidateStruct(
 		&tur,
 		/*
@@ -137,39 +389,393 @@ func (tur TicketUpdateRequest) Validate() error {
 
 		/*
 			Assignees validations:
-			- only allows frontend, backend, and design
+			- only allows the keys of the available teams
 			- must not contain duplicates
 		*/
 		validation.Field(
 			&tur.Assignees,
 			validation.Each(
-				validation.In("frontend", "backend", "design").Error("only allows \"frontend\", \"backend\", or \"design\""),
+				validation.In(toInterfaceSlice(rules.Teams)...).Error(allowedValuesMessage(rules.Teams)),
 			),
 			tur.Assignees.ValidateUniqueItems(),
 		),
 
+		/*
+			AssigneeIDs validations:
+			- only allows members of the workspace of the board
+			- must not contain duplicates
+		*/
+		validation.Field(
+			&tur.AssigneeIDs,
+			validation.Each(
+				validation.In(toInterfaceSlice(rules.Members)...).Error("must be a member of the workspace of the board"),
+			),
+			tur.AssigneeIDs.ValidateUniqueItems(),
+		),
+
 		/*
 			Status validations:
-			- only allows todo, doing, done
+			- only allows the column keys of the board workflow
 		*/
 		validation.Field(
 			&tur.Status,
-			validation.In("todo", "doing", "done").Error("only allows \"todo\", \"doing\", or \"done\""),
+			validation.In(toInterfaceSlice(rules.Statuses)...).Error(allowedValuesMessage(rules.Statuses)),
+		),
+
+		/*
+			Priority validations:
+			- only allows low, medium, high, urgent
+		*/
+		validation.Field(
+			&tur.Priority,
+			validation.In(toInterfaceSlice(TICKET_PRIORITIES)...).Error(allowedValuesMessage(TICKET_PRIORITIES)),
+		),
+
+		/*
+			Estimate validations:
+			- min 0
+			- max 100
+		*/
+		validation.Field(
+			&tur.Estimate,
+			validation.Min(0).Error("must be at least 0"),
+			validation.Max(MAX_TICKET_ESTIMATE).Error(fmt.Sprintf("must be at most %d", MAX_TICKET_ESTIMATE)),
+		),
+	)
+}
+
+/*
+TicketMoveRequest moves a ticket to a column and places it between
+two neighbours in that column:
+- BeforeID is the ticket that ends up right above the moved ticket
+- AfterID is the ticket that ends up right below the moved ticket
+
+When both are empty, the ticket is placed at the bottom of the column.
+*/
+type TicketMoveRequest struct {
+	Status   string `json:"status"`
+	BeforeID string `json:"before_id"`
+	AfterID  string `json:"after_id"`
+}
+
+func (tmr TicketMoveRequest) Validate(rules TicketRules) error {
+	return validation.ValidateStruct(
+		&tmr,
+		/*
+			Status validations:
+			- is required
+			- only allows the column keys of the board workflow
+		*/
+		validation.Field(
+			&tmr.Status,
+			validation.Required.Error("is required"),
+			validation.In(toInterfaceSlice(rules.Statuses)...).Error(allowedValuesMessage(rules.Statuses)),
+		),
+
+		/*
+			AfterID validations:
+			- must be different from BeforeID
+		*/
+		validation.Field(
+			&tmr.AfterID,
+			validation.NotIn(tmr.BeforeID).Error("must be different from before_id"),
 		),
 	)
 }
 
+const (
+	DEFAULT_TICKET_LIST_LIMIT = 50
+	MAX_TICKET_LIST_LIMIT     = 100
+)
+
+// assignee of the ticket list that stands for the user making the request
+const TICKET_ASSIGNEE_ME = "me"
+
+// fields the ticket list can be sorted by
+var TICKET_SORT_FIELDS []string = []string{"rank", "created_at", "updated_at", "title"}
+
+// query params
+type TicketListQuery struct {
+	Q             string     `form:"q"`
+	Title         string     `form:"title"`
+	Status        []string   `form:"status"`
+	Assignee      []string   `form:"assignee"`
+	Label         []string   `form:"label"`
+	Priority      []string   `form:"priority"`
+	MinPriority   string     `form:"min_priority"`
+	Overdue       bool       `form:"overdue"`
+	DueAfter      *time.Time `form:"due_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	DueBefore     *time.Time `form:"due_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedAfter  *time.Time `form:"updated_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedBefore *time.Time `form:"updated_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	SortBy        string     `form:"sort_by"`
+	Order         string     `form:"order"`
+	Limit         int        `form:"limit"`
+	Cursor        string     `form:"cursor"`
+}
+
+func (tlq TicketListQuery) Validate() error {
+	return validation.ValidateStruct(
+		&tlq,
+		/*
+			Q validations:
+			- max length 500
+			- must be a valid query of the ticket query language
+		*/
+		validation.Field(
+			&tlq.Q,
+			validation.Length(0, 500).Error("must have length of at most 500"),
+			validation.By(func(value interface{}) error {
+				_, err := TicketQuery(tlq.Q, nil, time.Now())
+				return err
+			}),
+		),
+
+		/*
+			SortBy validations:
+			- only allows rank, created_at, updated_at, title
+		*/
+		validation.Field(
+			&tlq.SortBy,
+			validation.In(toInterfaceSlice(TICKET_SORT_FIELDS)...).Error(allowedValuesMessage(TICKET_SORT_FIELDS)),
+		),
+
+		/*
+			Priority validations:
+			- only allows low, medium, high, urgent
+		*/
+		validation.Field(
+			&tlq.Priority,
+			validation.Each(
+				validation.In(toInterfaceSlice(TICKET_PRIORITIES)...).Error(allowedValuesMessage(TICKET_PRIORITIES)),
+			),
+		),
+
+		/*
+			MinPriority validations:
+			- only allows low, medium, high, urgent
+		*/
+		validation.Field(
+			&tlq.MinPriority,
+			validation.In(toInterfaceSlice(TICKET_PRIORITIES)...).Error(allowedValuesMessage(TICKET_PRIORITIES)),
+		),
+
+		/*
+			Order validations:
+			- only allows asc, desc
+		*/
+		validation.Field(
+			&tlq.Order,
+			validation.In("asc", "desc").Error("only allows \"asc\" or \"desc\""),
+		),
+
+		/*
+			Limit validations:
+			- min 1
+			- max 100
+		*/
+		validation.Field(
+			&tlq.Limit,
+			validation.Min(1).Error("must be at least 1"),
+			validation.Max(MAX_TICKET_LIST_LIMIT).Error(fmt.Sprintf("must be at most %d", MAX_TICKET_LIST_LIMIT)),
+		),
+
+		/*
+			Cursor validations:
+			- must be a cursor returned by a previous page
+		*/
+		validation.Field(
+			&tlq.Cursor,
+			validation.By(func(value interface{}) error {
+				if tlq.Cursor == "" {
+					return nil
+				}
+				_, err := DecodeCursor(tlq.Cursor)
+				return err
+			}),
+		),
+	)
+}
+
+func (tlq TicketListQuery) WithDefaults() TicketListQuery {
+	if tlq.SortBy == "" {
+		tlq.SortBy = "rank"
+	}
+	if tlq.Order == "" {
+		tlq.Order = "asc"
+	}
+	if tlq.Limit == 0 {
+		tlq.Limit = DEFAULT_TICKET_LIST_LIMIT
+	}
+	return tlq
+}
+
+// Filters narrows down the tickets based on the query params, the user is who "me" refers to
+func (tlq TicketListQuery) Filters(user *User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tlq.Q != "" {
+			// already validated
+			query, _ := TicketQuery(tlq.Q, user, time.Now())
+			db = db.Scopes(query)
+		}
+		if tlq.Title != "" {
+			db = db.Where("LOWER(tickets.title) like LOWER(?)", "%"+tlq.Title+"%")
+		}
+		if len(tlq.Status) > 0 {
+			db = db.Where("tickets.status IN ?", tlq.Status)
+		}
+		if len(tlq.Assignee) > 0 {
+			sql, args, _ := anyOf(tlq.Assignee, func(value string) (string, []interface{}, error) {
+				return assigneeCondition(value, user)
+			})
+			db = db.Where(sql, args...)
+		}
+		if len(tlq.Label) > 0 {
+			db = db.Where("EXISTS (SELECT 1 FROM ticket_labels JOIN labels ON labels.id = ticket_labels.label_id WHERE ticket_labels.ticket_id = tickets.id AND labels.name IN ?)", tlq.Label)
+		}
+		if len(tlq.Priority) > 0 {
+			db = db.Where("tickets.priority IN ?", tlq.Priority)
+		}
+		if tlq.MinPriority != "" {
+			db = db.Where("tickets.priority IN ?", PrioritiesFrom(tlq.MinPriority))
+		}
+		if tlq.Overdue {
+			db = db.Scopes(TicketsOverdue)
+		}
+		if tlq.DueAfter != nil {
+			db = db.Where("tickets.due_at >= ?", *tlq.DueAfter)
+		}
+		if tlq.DueBefore != nil {
+			db = db.Where("tickets.due_at < ?", *tlq.DueBefore)
+		}
+		if tlq.CreatedAfter != nil {
+			db = db.Where("tickets.created_at >= ?", *tlq.CreatedAfter)
+		}
+		if tlq.CreatedBefore != nil {
+			db = db.Where("tickets.created_at < ?", *tlq.CreatedBefore)
+		}
+		if tlq.UpdatedAfter != nil {
+			db = db.Where("tickets.updated_at >= ?", *tlq.UpdatedAfter)
+		}
+		if tlq.UpdatedBefore != nil {
+			db = db.Where("tickets.updated_at < ?", *tlq.UpdatedBefore)
+		}
+		return db
+	}
+}
+
+/*
+assigneeCondition matches tickets assigned to a team, or to the user when
+the value is "me". Without a user, e.g. when a query is only validated,
+"me" matches no ticket.
+*/
+func assigneeCondition(value string, user *User) (string, []interface{}, error) {
+	if value == TICKET_ASSIGNEE_ME {
+		if user == nil {
+			return "false", nil, nil
+		}
+		return "EXISTS (SELECT 1 FROM ticket_assignees WHERE ticket_assignees.ticket_id = tickets.id AND ticket_assignees.user_id = ?)", []interface{}{user.ID}, nil
+	}
+	return "EXISTS (SELECT 1 FROM jsonb_array_elements_text(tickets.assignees) AS assignee WHERE assignee = ?)", []interface{}{value}, nil
+}
+
+/*
+Page sorts the tickets and only returns the tickets after the cursor.
+One more ticket than the limit is returned so the caller knows whether
+there is a next page. The ticket ID breaks ties between equal values.
+*/
+func (tlq TicketListQuery) Page(cursor *Cursor) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		column := "tickets." + tlq.SortBy
+		direction, operator := "ASC", ">"
+		if tlq.Order == "desc" {
+			direction, operator = "DESC", "<"
+		}
+
+		if cursor != nil {
+			db = db.Where(fmt.Sprintf("(%s, tickets.id) %s (?, ?)", column, operator), cursor.Value, cursor.ID)
+		}
+
+		return db.Order(column + " " + direction + ", tickets.id " + direction).Limit(tlq.Limit + 1)
+	}
+}
+
+// SortValue returns the value of the field the ticket list is sorted by
+func (t *Ticket) SortValue(field string) string {
+	switch field {
+	case "created_at":
+		return t.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		return t.UpdatedAt.Format(time.RFC3339Nano)
+	case "title":
+		return t.Title
+	default:
+		return t.Rank
+	}
+}
+
 // response
 type TicketResponse struct {
-	ID          string      `json:"id"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Assignees   StringArray `json:"assignees"`
-	Status      string      `json:"status"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
+	ID            string            `json:"id"`
+	Key           string            `json:"key"`
+	BoardID       string            `json:"board_id"`
+	Title         string            `json:"title"`
+	Description   string            `json:"description"`
+	Assignees     StringArray       `json:"assignees"`
+	AssigneeUsers []UserResponse    `json:"assignee_users"`
+	Status        string            `json:"status"`
+	DueAt         *time.Time        `json:"due_at"`
+	Priority      string            `json:"priority"`
+	Estimate      *int              `json:"estimate"`
+	Rank          string            `json:"rank"`
+	Version       int               `json:"version"`
+	Labels        []LabelResponse   `json:"labels"`
+	Checklist     ChecklistProgress `json:"checklist"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	DeletedAt     *time.Time        `json:"deleted_at,omitempty"`
+
+	// only set on a single ticket
+	Links *TicketLinksResponse `json:"links,omitempty"`
+}
+
+type TicketListResponse struct {
+	Data       []TicketResponse `json:"data"`
+	NextCursor string           `json:"next_cursor"`
+	// WIP limits and ticket counts of the columns, only on the ticket list of a board
+	Columns []ColumnWIPResponse `json:"columns,omitempty"`
 }
 
 type TicketDeleteResponse struct {
 	Message string `json:"message"`
 }
+
+// helpers
+func toInterfaceSlice(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// e.g. only allows "todo", "doing", or "done"
+func allowedValuesMessage(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+
+	switch len(quoted) {
+	case 0:
+		return "no values are allowed"
+	case 1:
+		return "only allows " + quoted[0]
+	case 2:
+		return "only allows " + quoted[0] + " or " + quoted[1]
+	default:
+		return "only allows " + strings.Join(quoted[:len(quoted)-1], ", ") + ", or " + quoted[len(quoted)-1]
+	}
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"encoding/json"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	TICKET_BATCH_CREATE = "create"
+	TICKET_BATCH_UPDATE = "update"
+	TICKET_BATCH_MOVE   = "move"
+	TICKET_BATCH_DELETE = "delete"
+)
+
+var TICKET_BATCH_OPS = []string{TICKET_BATCH_CREATE, TICKET_BATCH_UPDATE, TICKET_BATCH_MOVE, TICKET_BATCH_DELETE}
+
+const MAX_TICKET_BATCH_OPERATIONS = 100
+
+// request body
+type TicketBatchRequest struct {
+	// when set, the whole batch is rolled back as soon as one operation fails
+	AllOrNothing bool                   `json:"all_or_nothing"`
+	Operations   []TicketBatchOperation `json:"operations"`
+}
+
+func (tbr TicketBatchRequest) Validate() error {
+	return validation.ValidateStruct(
+		&tbr,
+		/*
+			Operations validations:
+			- is required
+			- max MAX_TICKET_BATCH_OPERATIONS operations
+			- every operation is valid
+		*/
+		validation.Field(
+			&tbr.Operations,
+			validation.Required.Error("is required"),
+			validation.Length(1, MAX_TICKET_BATCH_OPERATIONS).Error("must have between 1 and 100 operations"),
+		),
+	)
+}
+
+/*
+only the fields of the op are used:
+- create: board_id (the default board is used when omitted) and ticket
+- update: ticket_id, if_match and patch (a JSON Merge Patch of the ticket)
+- move: ticket_id, if_match and move
+- delete: ticket_id and if_match
+*/
+type TicketBatchOperation struct {
+	Op       string               `json:"op" enums:"create,update,move,delete"`
+	TicketID string               `json:"ticket_id"`
+	BoardID  string               `json:"board_id"`
+	IfMatch  string               `json:"if_match"`
+	Ticket   *TicketCreateRequest `json:"ticket"`
+	Patch    json.RawMessage      `json:"patch" swaggertype:"object"`
+	Move     *TicketMoveRequest   `json:"move"`
+}
+
+func (tbo TicketBatchOperation) Validate() error {
+	return validation.ValidateStruct(
+		&tbo,
+		/*
+			Op validations:
+			- is required
+			- only allows create, update, move and delete
+		*/
+		validation.Field(
+			&tbo.Op,
+			validation.Required.Error("is required"),
+			validation.In(toInterfaceSlice(TICKET_BATCH_OPS)...).Error(allowedValuesMessage(TICKET_BATCH_OPS)),
+		),
+
+		/*
+			TicketID validations:
+			- is required for every op except create
+		*/
+		validation.Field(
+			&tbo.TicketID,
+			validation.When(tbo.Op != TICKET_BATCH_CREATE, validation.Required.Error("is required")),
+		),
+
+		/*
+			Ticket validations:
+			- is required for create
+		*/
+		validation.Field(
+			&tbo.Ticket,
+			validation.When(tbo.Op == TICKET_BATCH_CREATE, validation.Required.Error("is required")),
+		),
+
+		/*
+			Patch validations:
+			- is required for update
+		*/
+		validation.Field(
+			&tbo.Patch,
+			validation.When(tbo.Op == TICKET_BATCH_UPDATE, validation.Required.Error("is required")),
+		),
+
+		/*
+			Move validations:
+			- is required for move
+		*/
+		validation.Field(
+			&tbo.Move,
+			validation.When(tbo.Op == TICKET_BATCH_MOVE, validation.Required.Error("is required")),
+		),
+	)
+}
+
+// response
+type TicketBatchResult struct {
+	Op string `json:"op"`
+	// HTTP status the operation would have had as a single request
+	Status int             `json:"status"`
+	Ticket *TicketResponse `json:"ticket,omitempty"`
+	Error  interface{}     `json:"error,omitempty" swaggertype:"object"`
+}
+
+// results are in the order of the operations
+type TicketBatchResponse struct {
+	Results []TicketBatchResult `json:"results"`
+}
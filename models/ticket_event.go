@@ -0,0 +1,250 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	TICKET_EVENT_CREATED  = "created"
+	TICKET_EVENT_UPDATED  = "updated"
+	TICKET_EVENT_MOVED    = "moved"
+	TICKET_EVENT_DELETED  = "deleted"
+	TICKET_EVENT_RESTORED = "restored"
+)
+
+const (
+	DEFAULT_TICKET_EVENT_LIST_LIMIT = 50
+	MAX_TICKET_EVENT_LIST_LIMIT     = 100
+)
+
+/*
+TicketEvent records the change of a single field of a ticket. One action
+on a ticket (e.g. an update) creates one event for every changed field.
+Deleting a ticket creates a single event without a field. Restoring a
+ticket creates an event without a field followed by the changed fields.
+*/
+type TicketEvent struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Action    string    `gorm:"column:action;not null" json:"action"`
+	Field     string    `gorm:"column:field" json:"field"`
+	OldValue  JSONValue `gorm:"column:old_value;type:jsonb" json:"old_value"`
+	NewValue  JSONValue `gorm:"column:new_value;type:jsonb" json:"new_value"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create;index:idx_ticket_events_ticket_created,priority:2" json:"created_at"`
+
+	// belongs to
+	TicketID string `gorm:"not null;index:idx_ticket_events_ticket_created,priority:1" json:"ticket_id"`
+	Ticket   Ticket `json:"ticket"`
+	ActorID  string `gorm:"not null" json:"actor_id"`
+	Actor    User   `gorm:"foreignKey:ActorID" json:"actor"`
+}
+
+func (te *TicketEvent) TableName() string {
+	return "ticket_events"
+}
+
+func (te *TicketEvent) BeforeCreate(db *gorm.DB) error {
+	if te.ID == "" {
+		te.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+func (te *TicketEvent) ToTicketEventResponse() TicketEventResponse {
+	return TicketEventResponse{
+		ID:        te.ID,
+		TicketID:  te.TicketID,
+		Action:    te.Action,
+		Field:     te.Field,
+		OldValue:  te.OldValue,
+		NewValue:  te.NewValue,
+		Actor:     te.Actor.ToUserResponse(),
+		CreatedAt: te.CreatedAt,
+	}
+}
+
+// historyValues returns the fields of the ticket that are tracked in its history
+func (t *Ticket) historyValues() []ticketFieldValue {
+	return []ticketFieldValue{
+		{"title", t.Title},
+		{"description", t.Description},
+		{"assignees", t.Assignees},
+		{"assignee_ids", StringArray(t.AssigneeIDs())},
+		{"status", t.Status},
+		{"due_at", t.DueAt},
+		{"priority", t.Priority},
+		{"estimate", t.Estimate},
+		{"rank", t.Rank},
+		{"labels", StringArray(t.LabelNames())},
+	}
+}
+
+type ticketFieldValue struct {
+	field string
+	value any
+}
+
+/*
+NewTicketEvents returns the events for the fields that are different
+between two versions of a ticket. before is nil for a created ticket.
+All events of one action share the same timestamp.
+*/
+func NewTicketEvents(action string, actorID string, before *Ticket, after *Ticket) ([]TicketEvent, error) {
+	now := time.Now()
+	events := []TicketEvent{}
+
+	if action == TICKET_EVENT_DELETED || action == TICKET_EVENT_RESTORED {
+		events = append(events, TicketEvent{
+			Action:    action,
+			TicketID:  after.ID,
+			ActorID:   actorID,
+			CreatedAt: now,
+		})
+	}
+
+	if action == TICKET_EVENT_DELETED {
+		return events, nil
+	}
+
+	var beforeValues []ticketFieldValue
+	if before != nil {
+		beforeValues = before.historyValues()
+	}
+
+	for i, field := range after.historyValues() {
+		newValue, err := newTicketFieldValue(field.value)
+		if err != nil {
+			return nil, err
+		}
+
+		var oldValue JSONValue
+		if beforeValues != nil {
+			if oldValue, err = newTicketFieldValue(beforeValues[i].value); err != nil {
+				return nil, err
+			}
+		}
+
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+
+		events = append(events, TicketEvent{
+			Action:    action,
+			Field:     field.field,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			TicketID:  after.ID,
+			ActorID:   actorID,
+			CreatedAt: now,
+		})
+	}
+
+	return events, nil
+}
+
+// empty values are stored as null
+func newTicketFieldValue(value any) (JSONValue, error) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+	case StringArray:
+		if len(v) == 0 {
+			return nil, nil
+		}
+	case *time.Time:
+		if v == nil {
+			return nil, nil
+		}
+		// the same time can be loaded in different time zones
+		value = v.UTC()
+	case *int:
+		if v == nil {
+			return nil, nil
+		}
+	}
+
+	return NewJSONValue(value)
+}
+
+// query params
+type TicketEventListQuery struct {
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
+
+func (telq TicketEventListQuery) Validate() error {
+	return validation.ValidateStruct(
+		&telq,
+		/*
+			Limit validations:
+			- min 1
+			- max 100
+		*/
+		validation.Field(
+			&telq.Limit,
+			validation.Min(1).Error("must be at least 1"),
+			validation.Max(MAX_TICKET_EVENT_LIST_LIMIT).Error(fmt.Sprintf("must be at most %d", MAX_TICKET_EVENT_LIST_LIMIT)),
+		),
+
+		/*
+			Cursor validations:
+			- must be a cursor returned by a previous page
+		*/
+		validation.Field(
+			&telq.Cursor,
+			validation.By(func(value interface{}) error {
+				if telq.Cursor == "" {
+					return nil
+				}
+				_, err := DecodeCursor(telq.Cursor)
+				return err
+			}),
+		),
+	)
+}
+
+func (telq TicketEventListQuery) WithDefaults() TicketEventListQuery {
+	if telq.Limit == 0 {
+		telq.Limit = DEFAULT_TICKET_EVENT_LIST_LIMIT
+	}
+	return telq
+}
+
+/*
+Page returns the newest events first and only returns the events after
+the cursor. One more event than the limit is returned so the caller knows
+whether there is a next page.
+*/
+func (telq TicketEventListQuery) Page(cursor *Cursor) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if cursor != nil {
+			db = db.Where("(ticket_events.created_at, ticket_events.id) < (?, ?)", cursor.Value, cursor.ID)
+		}
+
+		return db.Order("ticket_events.created_at DESC, ticket_events.id DESC").Limit(telq.Limit + 1)
+	}
+}
+
+// response
+type TicketEventResponse struct {
+	ID        string       `json:"id"`
+	TicketID  string       `json:"ticket_id"`
+	Action    string       `json:"action"`
+	Field     string       `json:"field"`
+	OldValue  JSONValue    `json:"old_value" swaggertype:"object"`
+	NewValue  JSONValue    `json:"new_value" swaggertype:"object"`
+	Actor     UserResponse `json:"actor"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type TicketEventListResponse struct {
+	Data       []TicketEventResponse `json:"data"`
+	NextCursor string                `json:"next_cursor"`
+}
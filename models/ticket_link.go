@@ -0,0 +1,231 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	TICKET_LINK_BLOCKS     = "blocks"
+	TICKET_LINK_RELATES_TO = "relates_to"
+	TICKET_LINK_DUPLICATES = "duplicates"
+)
+
+var TICKET_LINK_TYPES = []string{TICKET_LINK_BLOCKS, TICKET_LINK_RELATES_TO, TICKET_LINK_DUPLICATES}
+
+/*
+TicketLink links a ticket to another ticket, read as "ticket <type>
+linked ticket", e.g. ticket A blocks ticket B. Tickets can be linked
+across boards. Links of the directed types blocks and duplicates can't
+form a cycle.
+*/
+type TicketLink struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Type      string    `gorm:"column:type;not null;uniqueIndex:idx_ticket_links_link;<-create" json:"type"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+
+	// belongs to
+	TicketID       string `gorm:"not null;uniqueIndex:idx_ticket_links_link;<-create" json:"ticket_id"`
+	Ticket         Ticket `json:"ticket"`
+	LinkedTicketID string `gorm:"not null;uniqueIndex:idx_ticket_links_link;index;<-create" json:"linked_ticket_id"`
+	LinkedTicket   Ticket `json:"linked_ticket"`
+	UserID         string `gorm:"not null" json:"user_id"`
+	User           User   `json:"user"`
+}
+
+func (tl *TicketLink) TableName() string {
+	return "ticket_links"
+}
+
+func (tl *TicketLink) BeforeCreate(db *gorm.DB) error {
+	if tl.ID == "" {
+		tl.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+func (tl *TicketLink) ToTicketLinkResponse() TicketLinkResponse {
+	return TicketLinkResponse{
+		ID:             tl.ID,
+		Type:           tl.Type,
+		TicketID:       tl.TicketID,
+		LinkedTicketID: tl.LinkedTicketID,
+		CreatedAt:      tl.CreatedAt,
+	}
+}
+
+// scopes
+
+// links from and to the ticket, together with the tickets on both ends
+func TicketLinksOf(ticketID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("ticket_links.ticket_id = ? OR ticket_links.linked_ticket_id = ?", ticketID, ticketID).
+			Preload("Ticket", selectTicketsDone).
+			Preload("LinkedTicket", selectTicketsDone).
+			Order("ticket_links.created_at ASC")
+	}
+}
+
+func selectTicketsDone(db *gorm.DB) *gorm.DB {
+	return db.Select("tickets.*, COALESCE(" + ticketsDoneCondition + ", false) AS done")
+}
+
+/*
+GetOpenBlockerIDs returns the tickets that block the ticket and are not
+done yet. Blockers in the trash don't block anymore.
+*/
+func GetOpenBlockerIDs(db *gorm.DB, ticketID string) ([]string, error) {
+	var ids []string
+	err := db.Model(&Ticket{}).
+		Joins("JOIN ticket_links ON ticket_links.ticket_id = tickets.id").
+		Where("ticket_links.type = ? AND ticket_links.linked_ticket_id = ?", TICKET_LINK_BLOCKS, ticketID).
+		Where("NOT COALESCE("+ticketsDoneCondition+", false)").
+		Order("tickets.id ASC").
+		Pluck("tickets.id", &ids).Error
+	return ids, err
+}
+
+/*
+TicketLinkCreatesCycle reports whether linking the ticket to the linked
+ticket closes a cycle, which is the case when the ticket can already be
+reached from the linked ticket through links of the same type.
+*/
+func TicketLinkCreatesCycle(db *gorm.DB, linkType string, ticketID string, linkedTicketID string) (bool, error) {
+	var cycle bool
+	err := db.Raw(`
+		WITH RECURSIVE reachable(id) AS (
+			SELECT ?::text
+			UNION
+			SELECT ticket_links.linked_ticket_id FROM ticket_links
+			JOIN reachable ON ticket_links.ticket_id = reachable.id
+			WHERE ticket_links.type = ?
+		)
+		SELECT EXISTS (SELECT 1 FROM reachable WHERE id = ?)`,
+		linkedTicketID, linkType, ticketID,
+	).Scan(&cycle).Error
+	return cycle, err
+}
+
+// request body
+type TicketLinkRequest struct {
+	Type string `json:"type"`
+	// ID or key of the linked ticket
+	TicketID string `json:"ticket_id"`
+}
+
+func (tlr TicketLinkRequest) Validate() error {
+	return validation.ValidateStruct(
+		&tlr,
+		/*
+			Type validations:
+			- is required
+			- only allows "blocks", "relates_to", or "duplicates"
+		*/
+		validation.Field(
+			&tlr.Type,
+			validation.Required.Error("is required"),
+			validation.In(toInterfaceSlice(TICKET_LINK_TYPES)...).Error(allowedValuesMessage(TICKET_LINK_TYPES)),
+		),
+
+		/*
+			TicketID validations:
+			- is required
+		*/
+		validation.Field(
+			&tlr.TicketID,
+			validation.Required.Error("is required"),
+		),
+	)
+}
+
+// response
+type TicketLinkResponse struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	TicketID       string    `json:"ticket_id"`
+	LinkedTicketID string    `json:"linked_ticket_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type LinkedTicketResponse struct {
+	LinkID  string `json:"link_id"`
+	ID      string `json:"id"`
+	Key     string `json:"key"`
+	BoardID string `json:"board_id"`
+	Title   string `json:"title"`
+	Status  string `json:"status"`
+	// whether the ticket is in the last column of its board
+	Done bool `json:"done"`
+}
+
+// the links of a ticket seen from that ticket
+type TicketLinksResponse struct {
+	Blocks       []LinkedTicketResponse `json:"blocks"`
+	BlockedBy    []LinkedTicketResponse `json:"blocked_by"`
+	RelatesTo    []LinkedTicketResponse `json:"relates_to"`
+	Duplicates   []LinkedTicketResponse `json:"duplicates"`
+	DuplicatedBy []LinkedTicketResponse `json:"duplicated_by"`
+}
+
+// NewTicketLinksResponse groups the links loaded by TicketLinksOf by the way they link the ticket
+func NewTicketLinksResponse(ticketID string, links []TicketLink) TicketLinksResponse {
+	response := TicketLinksResponse{
+		Blocks:       []LinkedTicketResponse{},
+		BlockedBy:    []LinkedTicketResponse{},
+		RelatesTo:    []LinkedTicketResponse{},
+		Duplicates:   []LinkedTicketResponse{},
+		DuplicatedBy: []LinkedTicketResponse{},
+	}
+
+	for _, link := range links {
+		outgoing := link.TicketID == ticketID
+		other := link.LinkedTicket
+		if !outgoing {
+			other = link.Ticket
+		}
+
+		// the other ticket is in the trash
+		if other.ID == "" {
+			continue
+		}
+
+		linked := LinkedTicketResponse{
+			LinkID:  link.ID,
+			ID:      other.ID,
+			Key:     other.Key,
+			BoardID: other.BoardID,
+			Title:   other.Title,
+			Status:  other.Status,
+			Done:    other.Done,
+		}
+
+		switch {
+		case link.Type == TICKET_LINK_BLOCKS && outgoing:
+			response.Blocks = append(response.Blocks, linked)
+		case link.Type == TICKET_LINK_BLOCKS:
+			response.BlockedBy = append(response.BlockedBy, linked)
+		case link.Type == TICKET_LINK_DUPLICATES && outgoing:
+			response.Duplicates = append(response.Duplicates, linked)
+		case link.Type == TICKET_LINK_DUPLICATES:
+			response.DuplicatedBy = append(response.DuplicatedBy, linked)
+		default:
+			response.RelatesTo = append(response.RelatesTo, linked)
+		}
+	}
+
+	return response
+}
+
+type TicketLinkDeleteResponse struct {
+	Message string `json:"message"`
+}
+
+// returned when a blocked ticket is started before its blockers are done
+type TicketBlockedErrorMessage struct {
+	Message   string   `json:"message"`
+	BlockedBy []string `json:"blocked_by"`
+}
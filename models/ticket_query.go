@@ -0,0 +1,230 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	queryhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/query"
+	"gorm.io/gorm"
+)
+
+var (
+	equalOperator   = []string{":"}
+	compareOperator = queryhelper.OPERATORS
+)
+
+// fields of the ticket query language and the operators they support
+var TICKET_QUERY_FIELDS = map[string][]string{
+	"status":   equalOperator,
+	"assignee": equalOperator,
+	"label":    equalOperator,
+	"title":    equalOperator,
+	"is":       equalOperator,
+	"priority": compareOperator,
+	"estimate": compareOperator,
+	"due":      compareOperator,
+	"created":  compareOperator,
+	"updated":  compareOperator,
+}
+
+// values of the "is" field
+var TICKET_QUERY_STATES = []string{"overdue", "unassigned", "unestimated"}
+
+/*
+TicketQuery converts a query of the ticket query language (see
+queryhelper.Parse) into conditions on the tickets. The user is who
+"assignee:me" refers to. Relative times are relative to now. Free text is
+matched against the search vector of the tickets, so words are matched by
+their stem.
+*/
+func TicketQuery(query string, user *User, now time.Time) (func(db *gorm.DB) *gorm.DB, error) {
+	terms, err := queryhelper.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	type condition struct {
+		sql  string
+		args []interface{}
+	}
+
+	conditions := make([]condition, 0, len(terms))
+	for _, term := range terms {
+		sql, args, err := ticketQueryCondition(term, user, now)
+		if err != nil {
+			return nil, err
+		}
+
+		// conditions on empty columns are NULL, which is neither true nor false
+		if term.Negated {
+			sql = "NOT COALESCE((" + sql + "), false)"
+		}
+
+		conditions = append(conditions, condition{sql: sql, args: args})
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		for _, condition := range conditions {
+			db = db.Where(condition.sql, condition.args...)
+		}
+		return db
+	}, nil
+}
+
+func ticketQueryCondition(term queryhelper.Term, user *User, now time.Time) (string, []interface{}, error) {
+	if term.Field == "" {
+		function := "plainto_tsquery"
+		if term.Phrase {
+			function = "phraseto_tsquery"
+		}
+		return "tickets.search_vector @@ " + function + "(?::regconfig, ?)", []interface{}{SearchLanguage(), term.Values[0]}, nil
+	}
+
+	operators, ok := TICKET_QUERY_FIELDS[term.Field]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown field %q", term.Field)
+	}
+	if !slices.Contains(operators, term.Operator) {
+		return "", nil, fmt.Errorf("%q %s as operator", term.Field, allowedValuesMessage(operators))
+	}
+	if term.Operator != ":" && len(term.Values) > 1 {
+		return "", nil, fmt.Errorf("%q%s only allows a single value", term.Field, term.Operator)
+	}
+
+	switch term.Field {
+	case "status":
+		return "tickets.status IN ?", []interface{}{term.Values}, nil
+	case "assignee":
+		return anyOf(term.Values, func(value string) (string, []interface{}, error) {
+			return assigneeCondition(value, user)
+		})
+	case "label":
+		return "EXISTS (SELECT 1 FROM ticket_labels JOIN labels ON labels.id = ticket_labels.label_id WHERE ticket_labels.ticket_id = tickets.id AND labels.name IN ?)", []interface{}{term.Values}, nil
+	case "title":
+		return anyOf(term.Values, func(value string) (string, []interface{}, error) {
+			return `tickets.title ILIKE ? ESCAPE '\'`, []interface{}{"%" + escapeLike(value) + "%"}, nil
+		})
+	case "is":
+		return anyOf(term.Values, func(value string) (string, []interface{}, error) {
+			return ticketStateCondition(value, now)
+		})
+	case "priority":
+		return anyOf(term.Values, func(value string) (string, []interface{}, error) {
+			priorities, err := comparePriorities(term.Operator, value)
+			return "tickets.priority IN ?", []interface{}{priorities}, err
+		})
+	case "estimate":
+		return anyOf(term.Values, func(value string) (string, []interface{}, error) {
+			estimate, err := strconv.Atoi(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid estimate %q", value)
+			}
+			return "tickets.estimate " + sqlOperator(term.Operator) + " ?", []interface{}{estimate}, nil
+		})
+	default:
+		column := "tickets." + term.Field + "_at"
+		return anyOf(term.Values, func(value string) (string, []interface{}, error) {
+			return timeCondition(column, term.Operator, value, now)
+		})
+	}
+}
+
+// escapeLike escapes the wildcards of LIKE patterns, so the value is matched literally
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// anyOf matches any of the values of a term
+func anyOf(values []string, condition func(value string) (string, []interface{}, error)) (string, []interface{}, error) {
+	sqls := make([]string, 0, len(values))
+	var args []interface{}
+	for _, value := range values {
+		sql, valueArgs, err := condition(value)
+		if err != nil {
+			return "", nil, err
+		}
+		sqls = append(sqls, "("+sql+")")
+		args = append(args, valueArgs...)
+	}
+
+	return strings.Join(sqls, " OR "), args, nil
+}
+
+func ticketStateCondition(state string, now time.Time) (string, []interface{}, error) {
+	switch state {
+	case "overdue":
+		return ticketsOverdueCondition, []interface{}{now}, nil
+	case "unassigned":
+		return "jsonb_array_length(tickets.assignees) = 0 AND NOT EXISTS (SELECT 1 FROM ticket_assignees WHERE ticket_assignees.ticket_id = tickets.id)", nil, nil
+	case "unestimated":
+		return "tickets.estimate IS NULL", nil, nil
+	default:
+		return "", nil, fmt.Errorf("\"is\" %s", allowedValuesMessage(TICKET_QUERY_STATES))
+	}
+}
+
+// comparePriorities returns the priorities that match the comparison
+func comparePriorities(operator string, priority string) ([]string, error) {
+	i := slices.Index(TICKET_PRIORITIES, priority)
+	if i < 0 {
+		return nil, fmt.Errorf("\"priority\" %s", allowedValuesMessage(TICKET_PRIORITIES))
+	}
+
+	switch operator {
+	case ">":
+		return TICKET_PRIORITIES[i+1:], nil
+	case ">=":
+		return TICKET_PRIORITIES[i:], nil
+	case "<":
+		return TICKET_PRIORITIES[:i], nil
+	case "<=":
+		return TICKET_PRIORITIES[:i+1], nil
+	default:
+		return []string{priority}, nil
+	}
+}
+
+/*
+timeCondition compares a time column. Days are compared as a whole, so
+due:today matches the whole day and due>today starts tomorrow. Other
+times given with ":" match from that time on.
+*/
+func timeCondition(column string, operator string, value string, now time.Time) (string, []interface{}, error) {
+	t, isDay, err := queryhelper.ParseTime(value, now)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !isDay {
+		if operator == ":" {
+			operator = ">="
+		}
+		return column + " " + sqlOperator(operator) + " ?", []interface{}{t}, nil
+	}
+
+	nextDay := t.AddDate(0, 0, 1)
+	switch operator {
+	case ">":
+		return column + " >= ?", []interface{}{nextDay}, nil
+	case ">=":
+		return column + " >= ?", []interface{}{t}, nil
+	case "<":
+		return column + " < ?", []interface{}{t}, nil
+	case "<=":
+		return column + " < ?", []interface{}{nextDay}, nil
+	default:
+		return column + " >= ? AND " + column + " < ?", []interface{}{t, nextDay}, nil
+	}
+}
+
+func sqlOperator(operator string) string {
+	if operator == ":" {
+		return "="
+	}
+	return operator
+}
@@ -0,0 +1,247 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	// placeholders of the title pattern of a template
+	TICKET_TEMPLATE_TITLE_PLACEHOLDER = "{title}"
+	TICKET_TEMPLATE_DATE_PLACEHOLDER  = "{date}"
+
+	MAX_TICKET_TEMPLATE_CHECKLIST_ITEMS = 50
+)
+
+// TicketTemplate pre-fills the fields and the checklist of new tickets on a board
+type TicketTemplate struct {
+	ID           string      `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Name         string      `gorm:"column:name;not null;uniqueIndex:idx_ticket_templates_board_name" json:"name"`
+	TitlePattern string      `gorm:"column:title_pattern;not null;default:''" json:"title_pattern"`
+	Description  string      `gorm:"column:description;not null;default:''" json:"description"`
+	Assignees    StringArray `gorm:"column:assignees;type:jsonb" json:"assignees"`
+	Status       string      `gorm:"column:status;not null;default:''" json:"status"`
+	Priority     string      `gorm:"column:priority;not null;default:''" json:"priority"`
+	Estimate     *int        `gorm:"column:estimate" json:"estimate"`
+	// texts of the checklist items
+	Checklist StringArray `gorm:"column:checklist;type:jsonb" json:"checklist"`
+	CreatedAt time.Time   `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt time.Time   `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+
+	// belongs to
+	BoardID string `gorm:"not null;uniqueIndex:idx_ticket_templates_board_name;<-create" json:"board_id"`
+	Board   Board  `json:"board"`
+}
+
+func (tt *TicketTemplate) TableName() string {
+	return "ticket_templates"
+}
+
+func (tt *TicketTemplate) BeforeCreate(db *gorm.DB) error {
+	if tt.ID == "" {
+		tt.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+func (tt *TicketTemplate) ToTicketTemplateResponse() TicketTemplateResponse {
+	response := TicketTemplateResponse{
+		ID:           tt.ID,
+		BoardID:      tt.BoardID,
+		Name:         tt.Name,
+		TitlePattern: tt.TitlePattern,
+		Description:  tt.Description,
+		Assignees:    tt.Assignees,
+		Status:       tt.Status,
+		Priority:     tt.Priority,
+		Estimate:     tt.Estimate,
+		Checklist:    tt.Checklist,
+		CreatedAt:    tt.CreatedAt,
+		UpdatedAt:    tt.UpdatedAt,
+	}
+
+	if response.Assignees == nil {
+		response.Assignees = StringArray{}
+	}
+	if response.Checklist == nil {
+		response.Checklist = StringArray{}
+	}
+
+	return response
+}
+
+/*
+Apply merges the template into the request of a new ticket. Fields set in
+the request win over the fields of the template. The title pattern is
+used when the request has no title or when the pattern contains {title},
+which is replaced by the title of the request. {date} is replaced by the
+current date.
+*/
+func (tt *TicketTemplate) Apply(reqBody TicketCreateRequest, now time.Time) TicketCreateRequest {
+	if reqBody.Title == "" || strings.Contains(tt.TitlePattern, TICKET_TEMPLATE_TITLE_PLACEHOLDER) {
+		title := strings.ReplaceAll(tt.TitlePattern, TICKET_TEMPLATE_TITLE_PLACEHOLDER, reqBody.Title)
+		title = strings.ReplaceAll(title, TICKET_TEMPLATE_DATE_PLACEHOLDER, now.Format(time.DateOnly))
+		reqBody.Title = strings.TrimSpace(title)
+	}
+
+	if reqBody.Description == "" {
+		reqBody.Description = tt.Description
+	}
+	if len(reqBody.Assignees) == 0 {
+		reqBody.Assignees = tt.Assignees
+	}
+	if reqBody.Status == "" {
+		reqBody.Status = tt.Status
+	}
+	if reqBody.Priority == "" {
+		reqBody.Priority = tt.Priority
+	}
+	if reqBody.Estimate == nil {
+		reqBody.Estimate = tt.Estimate
+	}
+
+	return reqBody
+}
+
+// scopes
+func TicketTemplatesVisibleTo(user *User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		visibleBoards := db.Session(&gorm.Session{NewDB: true}).Model(&Board{}).Select("boards.id").Scopes(BoardsVisibleTo(user))
+		return db.Where("ticket_templates.board_id IN (?)", visibleBoards)
+	}
+}
+
+// request body
+type TicketTemplateRequest struct {
+	Name         string      `json:"name"`
+	TitlePattern string      `json:"title_pattern"`
+	Description  string      `json:"description"`
+	Assignees    StringArray `json:"assignees"`
+	Status       string      `json:"status"`
+	Priority     string      `json:"priority"`
+	Estimate     *int        `json:"estimate"`
+	Checklist    StringArray `json:"checklist"`
+}
+
+// the fields are checked against the rules of the board, the title is checked once it is filled in
+func (ttr TicketTemplateRequest) Validate(rules TicketRules) error {
+	return validation.ValidateStruct(
+		&ttr,
+		/*
+			Name validations:
+			- is required
+			- min length 1
+			- max length 50
+		*/
+		validation.Field(
+			&ttr.Name,
+			validation.Required.Error("is required"),
+			validation.Length(1, 50).Error("must have length between 1 and 50"),
+		),
+
+		/*
+			TitlePattern validations:
+			- max length 100
+		*/
+		validation.Field(
+			&ttr.TitlePattern,
+			validation.Length(0, 100).Error("must have length between 0 and 100"),
+		),
+
+		/*
+			Description validations:
+			- min length 1
+			- max length 200
+		*/
+		validation.Field(
+			&ttr.Description,
+			validation.Length(1, 200).Error("must have length between 1 and 200"),
+		),
+
+		/*
+			Assignees validations:
+			- only allows the keys of the available teams
+			- must not contain duplicates
+		*/
+		validation.Field(
+			&ttr.Assignees,
+			validation.Each(
+				validation.In(toInterfaceSlice(rules.Teams)...).Error(allowedValuesMessage(rules.Teams)),
+			),
+			ttr.Assignees.ValidateUniqueItems(),
+		),
+
+		/*
+			Status validations:
+			- only allows the column keys of the board workflow
+		*/
+		validation.Field(
+			&ttr.Status,
+			validation.In(toInterfaceSlice(rules.Statuses)...).Error(allowedValuesMessage(rules.Statuses)),
+		),
+
+		/*
+			Priority validations:
+			- only allows low, medium, high, urgent
+		*/
+		validation.Field(
+			&ttr.Priority,
+			validation.In(toInterfaceSlice(TICKET_PRIORITIES)...).Error(allowedValuesMessage(TICKET_PRIORITIES)),
+		),
+
+		/*
+			Estimate validations:
+			- min 0
+			- max 100
+		*/
+		validation.Field(
+			&ttr.Estimate,
+			validation.Min(0).Error("must be at least 0"),
+			validation.Max(MAX_TICKET_ESTIMATE).Error(fmt.Sprintf("must be at most %d", MAX_TICKET_ESTIMATE)),
+		),
+
+		/*
+			Checklist validations:
+			- max 50 items
+			- every item has length between 1 and 500
+		*/
+		validation.Field(
+			&ttr.Checklist,
+			validation.Length(0, MAX_TICKET_TEMPLATE_CHECKLIST_ITEMS).Error(fmt.Sprintf("must have at most %d items", MAX_TICKET_TEMPLATE_CHECKLIST_ITEMS)),
+			validation.By(func(value interface{}) error {
+				for _, text := range ttr.Checklist {
+					if length := len([]rune(strings.TrimSpace(text))); length < 1 || length > 500 {
+						return errors.New("items must have length between 1 and 500")
+					}
+				}
+				return nil
+			}),
+		),
+	)
+}
+
+// response
+type TicketTemplateResponse struct {
+	ID           string      `json:"id"`
+	BoardID      string      `json:"board_id"`
+	Name         string      `json:"name"`
+	TitlePattern string      `json:"title_pattern"`
+	Description  string      `json:"description"`
+	Assignees    StringArray `json:"assignees"`
+	Status       string      `json:"status"`
+	Priority     string      `json:"priority"`
+	Estimate     *int        `json:"estimate"`
+	Checklist    StringArray `json:"checklist"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+type TicketTemplateDeleteResponse struct {
+	Message string `json:"message"`
+}
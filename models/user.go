@@ -2,6 +2,8 @@ package models
 
 import (
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
@@ -10,11 +12,18 @@ import (
 	"gorm.io/gorm"
 )
 
+// username of users whose email doesn't have enough letters for a username
+const DEFAULT_USERNAME = "user"
+
+// usernames are lowercase, users can be mentioned by them, e.g. @jane_doe
+var usernamePattern = regexp.MustCompile("^[a-z0-9_]{3,30}$")
+
 type User struct {
 	ID        string         `gorm:"primary_key;column:id;not null;<-create" json:"id"`
 	FirstName string         `gorm:"column:first_name;not null" json:"first_name"`
 	LastName  string         `gorm:"column:last_name;not null" json:"last_name"`
 	Email     string         `gorm:"column:email;not null" json:"email"`
+	Username  string         `gorm:"column:username;not null;default:'';uniqueIndex:idx_users_username,where:username <> ''" json:"username"`
 	Password  string         `gorm:"column:password;not null" json:"password"`
 	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
@@ -29,6 +38,13 @@ func (u *User) BeforeCreate(db *gorm.DB) error {
 	if u.ID == "" {
 		u.ID = helpers.GenerateUUIDWithoutHyphen()
 	}
+	if u.Username == "" {
+		username, err := NewUsername(db.Session(&gorm.Session{NewDB: true}), u.Email)
+		if err != nil {
+			return err
+		}
+		u.Username = username
+	}
 	return nil
 }
 
@@ -38,17 +54,77 @@ func (u *User) ToUserResponse() UserResponse {
 		FirstName: u.FirstName,
 		LastName:  u.LastName,
 		Email:     u.Email,
+		Username:  u.Username,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
 	}
 }
 
+/*
+NewUsername derives a username from the email that no other user has yet:
+- the part before the @, with every character that isn't allowed replaced by _, e.g. jane.doe@example.com becomes jane_doe
+- a number is added when the username is taken, e.g. jane_doe2
+*/
+func NewUsername(db *gorm.DB, email string) (string, error) {
+	base := usernameFromEmail(email)
+
+	username := base
+	for i := 2; ; i++ {
+		taken, err := IsUsernameTaken(db, username)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return username, nil
+		}
+
+		username = base + strconv.Itoa(i)
+	}
+}
+
+// usernames of deleted users stay taken, old mentions must not point to someone else
+func IsUsernameTaken(db *gorm.DB, username string) (bool, error) {
+	var count int64
+	if err := db.Model(&User{}).Unscoped().Where("username = ?", username).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func usernameFromEmail(email string) string {
+	localPart, _, _ := strings.Cut(strings.ToLower(email), "@")
+	username := strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, localPart)
+
+	// leaves room for the number of a taken username
+	username = username[:min(25, len(username))]
+	if len(username) < 3 {
+		return DEFAULT_USERNAME
+	}
+	return username
+}
+
+// scopes
+
+// users are listed by their name, e.g. the assignees of a ticket
+func OrderUsers(db *gorm.DB) *gorm.DB {
+	return db.Order("users.first_name ASC, users.last_name ASC, users.id ASC")
+}
+
 // request body
 type UserCreateRequest struct {
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	Email     string `json:"email"`
-	Password  string `json:"password"`
+	// derived from the email when left out
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// token of an invitation to a workspace that is accepted with the new account
+	InvitationToken string `json:"invitation_token"`
 }
 
 func (ucr UserCreateRequest) Validate() error {
@@ -91,6 +167,15 @@ func (ucr UserCreateRequest) Validate() error {
 			is.Email.Error("must be in email format"),
 		),
 
+		/*
+			Username validations:
+			- between 3 and 30 lowercase letters, numbers and underscores
+		*/
+		validation.Field(
+			&ucr.Username,
+			validation.Match(usernamePattern).Error("must have between 3 and 30 lowercase letters, numbers or underscores"),
+		),
+
 		/*
 			Password validations:
 			- required
@@ -154,6 +239,7 @@ type UserResponse struct {
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
 	Email     string    `json:"email"`
+	Username  string    `json:"username"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -2,10 +2,20 @@ package models
 
 import "encoding/json"
 
-// TODO: improve this so it can accept any kinds of data
+/*
+only the resource the event is about is set. Checklist events also
+carry the ticket of the item. Batch events carry the events of all
+operations of the batch instead. Events are only sent to the members of
+the workspace of the ticket, notification events only to the recipient
+of the notification.
+*/
 type WSMessage struct {
-	Event  string         `json:"event"`
-	Ticket TicketResponse `json:"ticket"`
+	Event         string                 `json:"event"`
+	Ticket        *TicketResponse        `json:"ticket,omitempty"`
+	Comment       *CommentResponse       `json:"comment,omitempty"`
+	ChecklistItem *ChecklistItemResponse `json:"checklist_item,omitempty"`
+	Notification  *NotificationResponse  `json:"notification,omitempty"`
+	Events        []WSMessage            `json:"events,omitempty"`
 }
 
 func (m *WSMessage) ToJsonMarshal() ([]byte, error) {
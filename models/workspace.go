@@ -0,0 +1,172 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Manuel-Leleuly/kanban-flow-go/helpers"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const DEFAULT_WORKSPACE_NAME = "My Workspace"
+
+// Workspace groups the boards that are shared by its members
+type Workspace struct {
+	ID        string    `gorm:"column:id;primary_key;not null;<-create" json:"id"`
+	Name      string    `gorm:"column:name;not null" json:"name"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;not null;<-create" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime;not null" json:"updated_at"`
+
+	// the user whose personal workspace it is, a user has at most one
+	PersonalUserID *string `gorm:"column:personal_user_id;uniqueIndex" json:"-"`
+
+	// has many
+	Memberships []Membership `json:"memberships"`
+}
+
+func (w *Workspace) TableName() string {
+	return "workspaces"
+}
+
+func (w *Workspace) BeforeCreate(db *gorm.DB) error {
+	if w.ID == "" {
+		w.ID = helpers.GenerateUUIDWithoutHyphen()
+	}
+	return nil
+}
+
+// the role is the role of the user who requested the workspace
+func (w *Workspace) ToWorkspaceResponse(role string) WorkspaceResponse {
+	return WorkspaceResponse{
+		ID:        w.ID,
+		Name:      w.Name,
+		Role:      role,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// scopes
+func WorkspacesVisibleTo(user *User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("workspaces.id IN (?)", memberWorkspaces(db, user.ID))
+	}
+}
+
+// LockWorkspace locks the workspace until the end of the transaction, e.g. while a board key is picked
+func LockWorkspace(db *gorm.DB, workspaceID string) error {
+	var workspace Workspace
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", workspaceID).First(&workspace).Error
+}
+
+// CreateWorkspace creates a workspace with the user as its owner
+func CreateWorkspace(db *gorm.DB, userID string, name string) (*Workspace, error) {
+	workspace := Workspace{Name: name}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&workspace).Error; err != nil {
+			return err
+		}
+
+		return setUpWorkspace(tx, workspace.ID, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+/*
+GetPersonalWorkspace returns the personal workspace of the user. If the
+user doesn't have one yet, it will be created.
+*/
+func GetPersonalWorkspace(db *gorm.DB, userID string) (*Workspace, error) {
+	var workspace Workspace
+	err := db.Where("personal_user_id = ?", userID).First(&workspace).Error
+	if err == nil {
+		return &workspace, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := createPersonalWorkspace(db, userID); err != nil {
+		return nil, err
+	}
+
+	// the workspace could have been created by a concurrent request instead
+	if err := db.Where("personal_user_id = ?", userID).First(&workspace).Error; err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+/*
+LeavePersonalWorkspace makes the workspace a regular workspace when it's
+the personal workspace of the user, e.g. when the user isn't its owner
+anymore. The user gets a new personal workspace when it's needed.
+*/
+func LeavePersonalWorkspace(db *gorm.DB, userID string, workspaceID string) error {
+	return db.Model(&Workspace{}).Where("id = ? AND personal_user_id = ?", workspaceID, userID).UpdateColumn("personal_user_id", nil).Error
+}
+
+// the unique personal_user_id lets only one of concurrent requests create the workspace
+func createPersonalWorkspace(db *gorm.DB, userID string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		workspace := Workspace{Name: DEFAULT_WORKSPACE_NAME, PersonalUserID: &userID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&workspace)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		return setUpWorkspace(tx, workspace.ID, userID)
+	})
+}
+
+// setUpWorkspace makes the user the owner of a new workspace and adds the default teams to it
+func setUpWorkspace(db *gorm.DB, workspaceID string, userID string) error {
+	err := db.Create(&Membership{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Role:        WORKSPACE_ROLE_OWNER,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return CreateDefaultTeams(db, workspaceID, userID)
+}
+
+// request body
+type WorkspaceRequest struct {
+	Name string `json:"name"`
+}
+
+func (wr WorkspaceRequest) Validate() error {
+	return validation.ValidateStruct(
+		&wr,
+		/*
+			Name validations:
+			- is required
+			- min length 1
+			- max length 50
+		*/
+		validation.Field(
+			&wr.Name,
+			validation.Required.Error("is required"),
+			validation.Length(1, 50).Error("must have length between 1 and 50"),
+		),
+	)
+}
+
+// response
+type WorkspaceResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -18,6 +18,16 @@ func IAMV1Routes(router *gin.RouterGroup, d *models.DBInstance) {
 	{
 		withAccessToken.GET("/users/me", controllers.GetMe)
 		withAccessToken.POST("/logout", controllers.Logout)
+
+		withAccessToken.POST("/invitations", d.MakeHTTPHandleFunc(controllers.CreateInvitation))
+		withAccessToken.GET("/invitations", d.MakeHTTPHandleFunc(controllers.GetInvitationList))
+		withAccessToken.POST("/invitations/accept", d.MakeHTTPHandleFunc(controllers.AcceptInvitation))
+		withAccessToken.POST("/invitations/:invitationId/resend", d.MakeHTTPHandleFunc(controllers.ResendInvitation))
+		withAccessToken.DELETE("/invitations/:invitationId", d.MakeHTTPHandleFunc(controllers.RevokeInvitation))
+
+		withAccessToken.GET("/notifications", d.MakeHTTPHandleFunc(controllers.GetNotificationList))
+		withAccessToken.POST("/notifications/read-all", d.MakeHTTPHandleFunc(controllers.MarkAllNotificationsRead))
+		withAccessToken.POST("/notifications/:notificationId/read", d.MakeHTTPHandleFunc(controllers.MarkNotificationRead))
 	}
 
 	withRefreshToken := v1.Group("/", d.MakeHTTPHandleFunc(middlewares.CheckRefreshToken))
@@ -2,17 +2,100 @@ package kanbanv1
 
 import (
 	"github.com/Manuel-Leleuly/kanban-flow-go/controllers"
+	"github.com/Manuel-Leleuly/kanban-flow-go/middlewares"
 	"github.com/Manuel-Leleuly/kanban-flow-go/models"
 	"github.com/gin-gonic/gin"
 )
 
 func KanbanV1Routes(router *gin.RouterGroup, d *models.DBInstance) {
 	v1 := router.Group("/v1")
+
+	// minimum role in the workspace of the resources in the path
+	viewer := d.MakeHTTPHandleFunc(middlewares.CheckWorkspaceRole(models.WORKSPACE_ROLE_VIEWER))
+	member := d.MakeHTTPHandleFunc(middlewares.CheckWorkspaceRole(models.WORKSPACE_ROLE_MEMBER))
+	admin := d.MakeHTTPHandleFunc(middlewares.CheckWorkspaceRole(models.WORKSPACE_ROLE_ADMIN))
+
 	{
+		v1.POST("/workspaces", d.MakeHTTPHandleFunc(controllers.CreateWorkspace))
+		v1.GET("/workspaces", d.MakeHTTPHandleFunc(controllers.GetWorkspaceList))
+		v1.GET("/workspaces/:workspaceId", viewer, d.MakeHTTPHandleFunc(controllers.GetWorkspaceById))
+		v1.PUT("/workspaces/:workspaceId", admin, d.MakeHTTPHandleFunc(controllers.UpdateWorkspace))
+		v1.GET("/workspaces/:workspaceId/members", viewer, d.MakeHTTPHandleFunc(controllers.GetWorkspaceMemberList))
+		v1.POST("/workspaces/:workspaceId/members", admin, d.MakeHTTPHandleFunc(controllers.CreateWorkspaceMember))
+		v1.PUT("/workspaces/:workspaceId/members/:userId", admin, d.MakeHTTPHandleFunc(controllers.UpdateWorkspaceMember))
+		v1.DELETE("/workspaces/:workspaceId/members/:userId", admin, d.MakeHTTPHandleFunc(controllers.DeleteWorkspaceMember))
+		v1.POST("/workspaces/:workspaceId/boards", admin, d.MakeHTTPHandleFunc(controllers.CreateBoard))
+		v1.POST("/workspaces/:workspaceId/teams", admin, d.MakeHTTPHandleFunc(controllers.CreateTeam))
+		v1.GET("/workspaces/:workspaceId/teams", viewer, d.MakeHTTPHandleFunc(controllers.GetTeamList))
+
+		v1.POST("/boards", d.MakeHTTPHandleFunc(controllers.CreateBoard))
+		v1.GET("/boards", d.MakeHTTPHandleFunc(controllers.GetBoardList))
+		v1.GET("/boards/:boardId", viewer, d.MakeHTTPHandleFunc(controllers.GetBoardById))
+		v1.PUT("/boards/:boardId", admin, d.MakeHTTPHandleFunc(controllers.UpdateBoard))
+		v1.DELETE("/boards/:boardId", admin, d.MakeHTTPHandleFunc(controllers.DeleteBoard))
+		v1.GET("/boards/:boardId/columns", viewer, d.MakeHTTPHandleFunc(controllers.GetBoardColumns))
+		v1.PUT("/boards/:boardId/columns", admin, d.MakeHTTPHandleFunc(controllers.UpdateBoardColumns))
+		v1.POST("/boards/:boardId/tickets", member, d.MakeHTTPHandleFunc(controllers.CreateTicket))
+		v1.GET("/boards/:boardId/tickets", viewer, d.MakeHTTPHandleFunc(controllers.GetTicketList))
+		v1.POST("/boards/:boardId/labels", admin, d.MakeHTTPHandleFunc(controllers.CreateLabel))
+		v1.GET("/boards/:boardId/labels", viewer, d.MakeHTTPHandleFunc(controllers.GetLabelList))
+		v1.POST("/boards/:boardId/templates", admin, d.MakeHTTPHandleFunc(controllers.CreateTicketTemplate))
+		v1.GET("/boards/:boardId/templates", viewer, d.MakeHTTPHandleFunc(controllers.GetTicketTemplateList))
+
+		v1.PUT("/labels/:labelId", admin, d.MakeHTTPHandleFunc(controllers.UpdateLabel))
+		v1.DELETE("/labels/:labelId", admin, d.MakeHTTPHandleFunc(controllers.DeleteLabel))
+
+		v1.GET("/templates/:templateId", viewer, d.MakeHTTPHandleFunc(controllers.GetTicketTemplateById))
+		v1.PUT("/templates/:templateId", admin, d.MakeHTTPHandleFunc(controllers.UpdateTicketTemplate))
+		v1.DELETE("/templates/:templateId", admin, d.MakeHTTPHandleFunc(controllers.DeleteTicketTemplate))
+
+		v1.POST("/teams", d.MakeHTTPHandleFunc(controllers.CreateTeam))
+		v1.GET("/teams", d.MakeHTTPHandleFunc(controllers.GetTeamList))
+		v1.PUT("/teams/:teamId", admin, d.MakeHTTPHandleFunc(controllers.UpdateTeam))
+		v1.DELETE("/teams/:teamId", admin, d.MakeHTTPHandleFunc(controllers.DeleteTeam))
+
 		v1.POST("/tickets", d.MakeHTTPHandleFunc(controllers.CreateTicket))
 		v1.GET("/tickets", d.MakeHTTPHandleFunc(controllers.GetTicketList))
-		v1.GET("/tickets/:ticketId", d.MakeHTTPHandleFunc(controllers.GetTicketById))
-		v1.PUT("/tickets/:ticketId", d.MakeHTTPHandleFunc(controllers.UpdateTicket))
-		v1.DELETE("/tickets/:ticketId", d.MakeHTTPHandleFunc(controllers.DeleteTicket))
+		v1.POST("/tickets:action", d.MakeHTTPHandleFunc(controllers.BatchTickets))
+		v1.POST("/tickets/batch", d.MakeHTTPHandleFunc(controllers.BatchTickets))
+		v1.GET("/tickets/trash", d.MakeHTTPHandleFunc(controllers.GetTrashedTicketList))
+		v1.GET("/tickets/:ticketId", viewer, d.MakeHTTPHandleFunc(controllers.GetTicketById))
+		v1.PUT("/tickets/:ticketId", member, d.MakeHTTPHandleFunc(controllers.UpdateTicket))
+		v1.PATCH("/tickets/:ticketId", member, d.MakeHTTPHandleFunc(controllers.PatchTicket))
+		v1.DELETE("/tickets/:ticketId", member, d.MakeHTTPHandleFunc(controllers.DeleteTicket))
+		v1.POST("/tickets/:ticketId/move", member, d.MakeHTTPHandleFunc(controllers.MoveTicket))
+		v1.POST("/tickets/:ticketId/restore", member, d.MakeHTTPHandleFunc(controllers.RestoreTicket))
+		v1.DELETE("/tickets/:ticketId/purge", admin, d.MakeHTTPHandleFunc(controllers.PurgeTicket))
+		v1.GET("/tickets/:ticketId/history", viewer, d.MakeHTTPHandleFunc(controllers.GetTicketHistory))
+		v1.PUT("/tickets/:ticketId/labels/:labelId", member, d.MakeHTTPHandleFunc(controllers.AttachTicketLabel))
+		v1.DELETE("/tickets/:ticketId/labels/:labelId", member, d.MakeHTTPHandleFunc(controllers.DetachTicketLabel))
+
+		v1.POST("/tickets/:ticketId/comments", member, d.MakeHTTPHandleFunc(controllers.CreateComment))
+		v1.GET("/tickets/:ticketId/comments", viewer, d.MakeHTTPHandleFunc(controllers.GetCommentList))
+		v1.PUT("/tickets/:ticketId/comments/:commentId", member, d.MakeHTTPHandleFunc(controllers.UpdateComment))
+		v1.DELETE("/tickets/:ticketId/comments/:commentId", member, d.MakeHTTPHandleFunc(controllers.DeleteComment))
+
+		v1.POST("/tickets/:ticketId/checklist", member, d.MakeHTTPHandleFunc(controllers.CreateChecklistItem))
+		v1.GET("/tickets/:ticketId/checklist", viewer, d.MakeHTTPHandleFunc(controllers.GetChecklist))
+		v1.PUT("/tickets/:ticketId/checklist/:itemId", member, d.MakeHTTPHandleFunc(controllers.UpdateChecklistItem))
+		v1.DELETE("/tickets/:ticketId/checklist/:itemId", member, d.MakeHTTPHandleFunc(controllers.DeleteChecklistItem))
+		v1.POST("/tickets/:ticketId/checklist/:itemId/move", member, d.MakeHTTPHandleFunc(controllers.MoveChecklistItem))
+
+		v1.POST("/tickets/:ticketId/links", member, d.MakeHTTPHandleFunc(controllers.CreateTicketLink))
+		v1.GET("/tickets/:ticketId/links", viewer, d.MakeHTTPHandleFunc(controllers.GetTicketLinks))
+		v1.DELETE("/tickets/:ticketId/links/:linkId", member, d.MakeHTTPHandleFunc(controllers.DeleteTicketLink))
+
+		v1.POST("/tickets/:ticketId/attachments", member, d.MakeHTTPHandleFunc(controllers.CreateAttachment))
+		v1.GET("/tickets/:ticketId/attachments", viewer, d.MakeHTTPHandleFunc(controllers.GetAttachmentList))
+		v1.GET("/tickets/:ticketId/attachments/:attachmentId", viewer, d.MakeHTTPHandleFunc(controllers.GetAttachmentById))
+		v1.DELETE("/tickets/:ticketId/attachments/:attachmentId", member, d.MakeHTTPHandleFunc(controllers.DeleteAttachment))
+
+		v1.GET("/search", d.MakeHTTPHandleFunc(controllers.SearchTickets))
+
+		v1.POST("/views", d.MakeHTTPHandleFunc(controllers.CreateSavedView))
+		v1.GET("/views", d.MakeHTTPHandleFunc(controllers.GetSavedViewList))
+		v1.GET("/views/:viewId", d.MakeHTTPHandleFunc(controllers.GetSavedViewById))
+		v1.PUT("/views/:viewId", d.MakeHTTPHandleFunc(controllers.UpdateSavedView))
+		v1.DELETE("/views/:viewId", d.MakeHTTPHandleFunc(controllers.DeleteSavedView))
 	}
 }
@@ -39,8 +39,11 @@ func GetRoutes(d *models.DBInstance) *gin.Engine {
 	router.HEAD("/healthz", controllers.CheckServerHealth)
 	router.GET("/healthz", controllers.CheckServerHealth)
 
-	// implement websocket
-	router.GET("/ws", controllers.WebSocketHandler)
+	// implement websocket, clients only get the events of the workspaces of their user and their own notifications
+	router.GET("/ws", d.MakeHTTPHandleFunc(middlewares.CheckAccessToken), controllers.WebSocketHandler)
+
+	// attachment downloads are authorized by their signed URL
+	router.GET("/attachments/:attachmentId", d.MakeHTTPHandleFunc(controllers.DownloadAttachment))
 
 	iam.IAMRoutes(router, d)
 	kanban.KanbanRoutes(router, d)
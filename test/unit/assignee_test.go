@@ -0,0 +1,196 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssigneeSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	assignee, assigneeToken := createWorkspaceTestUser(t, router, "assignee@example.com")
+	board := createAssigneeBoard(t, router, token.AccessToken, assignee)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:       "Assigned ticket",
+		AssigneeIDs: models.StringArray{assignee.ID},
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	assert.Len(t, ticket.AssigneeUsers, 1)
+	assert.Equal(t, assignee.ID, ticket.AssigneeUsers[0].ID)
+	assert.Equal(t, assignee.Email, ticket.AssigneeUsers[0].Email)
+
+	// "me" is the user making the request
+	assert.Contains(t, getMyTicketIds(t, router, assigneeToken), ticket.ID)
+	assert.NotContains(t, getMyTicketIds(t, router, token.AccessToken), ticket.ID)
+
+	// patches that leave out assignee_ids keep the assignees
+	request = testhelper.GetHTTPRequest(http.MethodPatch, "/kanban/v1/tickets/"+ticket.ID, strings.NewReader(`{"title": "Renamed assigned ticket"}`), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	assert.Contains(t, getMyTicketIds(t, router, assigneeToken), ticket.ID)
+
+	// members that leave the workspace are unassigned
+	request = testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/workspaces/"+board.WorkspaceID+"/members/"+assignee.ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+ticket.ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	assert.Len(t, ticket.AssigneeUsers, 0)
+}
+
+func TestAssigneeFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	member, _ := createWorkspaceTestUser(t, router, "unassigned-member@example.com")
+	nonMember, _ := createWorkspaceTestUser(t, router, "non-member@example.com")
+	board := createAssigneeBoard(t, router, token.AccessToken, member)
+
+	// only members of the workspace can see the ticket
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:       "Assigned to a stranger",
+		AssigneeIDs: models.StringArray{nonMember.ID},
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// users are assigned once
+	ticketJson, err = json.Marshal(models.TicketCreateRequest{
+		Title:       "Assigned twice ticket",
+		AssigneeIDs: models.StringArray{member.ID, member.ID},
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+// helpers
+
+// createAssigneeBoard creates a board in a new workspace the member joins
+func createAssigneeBoard(t *testing.T, router *gin.Engine, accessToken string, member models.UserResponse) models.BoardResponse {
+	workspace := createTestWorkspace(t, router, accessToken)
+
+	memberJson, err := json.Marshal(models.MembershipCreateRequest{
+		Email: member.Email,
+		Role:  models.WORKSPACE_ROLE_MEMBER,
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/members", strings.NewReader(string(memberJson)), accessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	boardJson, err := json.Marshal(models.BoardCreateRequest{Name: "Assignee Board"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/boards", strings.NewReader(string(boardJson)), accessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var board models.BoardResponse
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	return board
+}
+
+func getMyTicketIds(t *testing.T, router *gin.Engine, accessToken string) []string {
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets?assignee=me", nil, accessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var tickets models.TicketListResponse
+	err = json.Unmarshal(body, &tickets)
+	assert.Nil(t, err)
+
+	ticketIds := []string{}
+	for _, ticket := range tickets.Data {
+		ticketIds = append(ticketIds, ticket.ID)
+	}
+	return ticketIds
+}
@@ -0,0 +1,235 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+// the signature of a PNG file followed by some bytes
+var testPNG = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+func TestCreateAttachmentSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	attachmentsUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/attachments"
+
+	request := testhelper.GetMultipartRequest(attachmentsUrl, "file", "../screenshot.png", testPNG, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var attachment models.AttachmentResponse
+	err = json.Unmarshal(body, &attachment)
+	assert.Nil(t, err)
+
+	// directories are dropped from the filename
+	assert.Equal(t, "screenshot.png", attachment.Filename)
+	assert.Equal(t, "image/png", attachment.ContentType)
+	assert.Equal(t, int64(len(testPNG)), attachment.Size)
+	assert.Equal(t, testhelper.TEST_USER.ID, attachment.Uploader.ID)
+
+	// the signed URL works without an access token
+	downloadUrl, err := url.Parse(attachment.DownloadURL)
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodGet, downloadUrl.RequestURI(), nil, "")
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "image/png", response.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=screenshot.png`, response.Header.Get("Content-Disposition"))
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, testPNG, body)
+}
+
+func TestCreateAttachmentFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	attachmentsUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/attachments"
+
+	// failed because the file is missing
+	request := testhelper.GetHTTPRequest(http.MethodPost, attachmentsUrl, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+
+	// failed because html files are not allowed, whatever their name says
+	request = testhelper.GetMultipartRequest(attachmentsUrl, "file", "screenshot.png", []byte("<html><script>alert(1)</script></html>"), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusUnsupportedMediaType, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "file type text/html; charset=utf-8 is not allowed", responseBody.Message)
+
+	// failed because the download URL was tampered with
+	request = testhelper.GetHTTPRequest(http.MethodGet, attachmentsUrl, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err = io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var attachments []models.AttachmentResponse
+	err = json.Unmarshal(body, &attachments)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, attachments)
+
+	downloadUrl, err := url.Parse(attachments[0].DownloadURL)
+	assert.Nil(t, err)
+
+	query := downloadUrl.Query()
+	query.Set("expires", "9999999999")
+	downloadUrl.RawQuery = query.Encode()
+
+	request = testhelper.GetHTTPRequest(http.MethodGet, downloadUrl.RequestURI(), nil, "")
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+}
+
+func TestDeleteAttachmentSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	attachmentsUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/attachments"
+
+	request := testhelper.GetHTTPRequest(http.MethodGet, attachmentsUrl, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var attachments []models.AttachmentResponse
+	err = json.Unmarshal(body, &attachments)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, attachments)
+
+	request = testhelper.GetHTTPRequest(http.MethodDelete, attachmentsUrl+"/"+attachments[0].ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.AttachmentDeleteResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "success", responseBody.Message)
+
+	// the file can't be downloaded anymore
+	downloadUrl, err := url.Parse(attachments[0].DownloadURL)
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodGet, downloadUrl.RequestURI(), nil, "")
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+}
+
+func TestDownloadTrashedAttachmentFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{Title: "Ticket with a trashed attachment"})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	request = testhelper.GetMultipartRequest("/kanban/v1/tickets/"+ticket.ID+"/attachments", "file", "trashed.png", testPNG, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var attachment models.AttachmentResponse
+	err = json.Unmarshal(body, &attachment)
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/tickets/"+ticket.ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+	// the signed URL stops working once the ticket is in the trash
+	downloadUrl, err := url.Parse(attachment.DownloadURL)
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodGet, downloadUrl.RequestURI(), nil, "")
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+}
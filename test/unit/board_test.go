@@ -0,0 +1,343 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateBoardSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	reqBody := models.BoardCreateRequest{
+		Name:        "New Test Board",
+		Description: "New Test Board Description",
+	}
+
+	boardJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.BoardResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, reqBody.Name, responseBody.Name)
+	assert.Equal(t, reqBody.Description, responseBody.Description)
+}
+
+func TestCreateBoardFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	reqBody := models.BoardCreateRequest{
+		Name:        "",
+		Description: "",
+	}
+
+	boardJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	// last validation ends with a dot
+	assert.Len(t, responseBody.Message, 1)
+	assert.Equal(t, "name: is required.", responseBody.Message[0])
+}
+
+func TestUpdateBoardSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	reqBody := models.BoardUpdateRequest{
+		Name:        "Updated Board",
+		Description: testhelper.TEST_BOARD.Description,
+	}
+
+	boardJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID, strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.BoardResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, testhelper.TEST_BOARD.ID, responseBody.ID)
+	assert.Equal(t, reqBody.Name, responseBody.Name)
+	assert.Equal(t, reqBody.Description, responseBody.Description)
+}
+
+func TestCreateBoardTicketSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	reqBody := models.TicketCreateRequest{
+		Title:       "New Board Ticket",
+		Description: "New Board Ticket Description",
+		Assignees:   []string{"backend"},
+		Status:      "todo",
+	}
+
+	ticketJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TicketResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, testhelper.TEST_BOARD.ID, responseBody.BoardID)
+	assert.Equal(t, reqBody.Title, responseBody.Title)
+}
+
+func TestDeleteBoardSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	boardJson, err := json.Marshal(models.BoardCreateRequest{Name: "Archived Board"})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var board models.BoardResponse
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{Title: "Archived ticket"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/boards/"+board.ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// the tickets of the board are deleted with it
+	var count int64
+	err = D.DB.Model(&models.TicketEvent{}).Where("ticket_id = ? AND action = ?", ticket.ID, models.TICKET_EVENT_DELETED).Count(&count).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestDeleteBoardFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	tokenData, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/boards/wrongboardid", nil, tokenData.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "board not found", responseBody.Message)
+}
+
+func TestUpdateBoardColumnsSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// use a new board so the workflow of the test board stays untouched
+	boardJson, err := json.Marshal(models.BoardCreateRequest{Name: "QA Board"})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var board models.BoardResponse
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	// add review and blocked columns
+	reqBody := models.BoardColumnsUpdateRequest{
+		Columns: []models.BoardColumnRequest{
+			{Key: "todo", Name: "To Do", Color: "#94a3b8"},
+			{Key: "doing", Name: "Doing", Color: "#3b82f6"},
+			{Key: "review", Name: "Review", Color: "#a855f7"},
+			{Key: "blocked", Name: "Blocked", Color: "#ef4444"},
+			{Key: "done", Name: "Done", Color: "#22c55e"},
+		},
+	}
+
+	columnsJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/boards/"+board.ID+"/columns", strings.NewReader(string(columnsJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var columnsResponseBody []models.BoardColumnResponse
+	err = json.Unmarshal(body, &columnsResponseBody)
+	assert.Nil(t, err)
+
+	assert.Len(t, columnsResponseBody, len(reqBody.Columns))
+	for i, column := range reqBody.Columns {
+		assert.Equal(t, column.Key, columnsResponseBody[i].Key)
+		assert.Equal(t, i, columnsResponseBody[i].Position)
+	}
+
+	// tickets can now be created in the review column
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:  "Ticket waiting for review",
+		Status: "review",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+}
+
+func TestUpdateBoardColumnsFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// the test ticket is still in the todo column
+	reqBody := models.BoardColumnsUpdateRequest{
+		Columns: []models.BoardColumnRequest{
+			{Key: "doing", Name: "Doing", Color: "#3b82f6"},
+			{Key: "done", Name: "Done", Color: "#22c55e"},
+		},
+	}
+
+	columnsJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/columns", strings.NewReader(string(columnsJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "column \"todo\" still has tickets and cannot be removed", responseBody.Message)
+}
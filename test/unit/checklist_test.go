@@ -0,0 +1,267 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateChecklistItemSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	checklistUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/checklist"
+
+	var items []models.ChecklistItemResponse
+	for _, reqBody := range []models.ChecklistItemRequest{
+		{Text: "Write the migration", Done: true, Assignee: "backend"},
+		{Text: "Update the settings page"},
+	} {
+		itemJson, err := json.Marshal(reqBody)
+		assert.Nil(t, err)
+
+		request := testhelper.GetHTTPRequest(http.MethodPost, checklistUrl, strings.NewReader(string(itemJson)), token.AccessToken)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response := recorder.Result()
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+		body, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		var item models.ChecklistItemResponse
+		err = json.Unmarshal(body, &item)
+		assert.Nil(t, err)
+
+		assert.Equal(t, reqBody.Text, item.Text)
+		assert.Equal(t, reqBody.Assignee, item.Assignee)
+		items = append(items, item)
+	}
+
+	// new items are added at the bottom
+	assert.Less(t, items[0].Rank, items[1].Rank)
+
+	// the progress of the checklist is part of the ticket
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	assert.Equal(t, models.NewChecklistProgress(1, 2), ticket.Checklist)
+}
+
+func TestCreateChecklistItemFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	checklistUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/checklist"
+
+	// failed because of validation
+	itemJson, err := json.Marshal(models.ChecklistItemRequest{
+		Text:     "",
+		Assignee: "marketing",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, checklistUrl, strings.NewReader(string(itemJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var validationResponseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &validationResponseBody)
+	assert.Nil(t, err)
+
+	assert.Len(t, validationResponseBody.Message, 2)
+
+	// failed because the ticket doesn't exist
+	itemJson, err = json.Marshal(models.ChecklistItemRequest{
+		Text: "Item of a missing ticket",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/missing/checklist", strings.NewReader(string(itemJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+}
+
+func TestMoveChecklistItemSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	checklistUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/checklist"
+
+	request := testhelper.GetHTTPRequest(http.MethodGet, checklistUrl, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var items []models.ChecklistItemResponse
+	err = json.Unmarshal(body, &items)
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, len(items), 2)
+
+	// move the last item to the top
+	last := items[len(items)-1]
+	moveJson, err := json.Marshal(models.ChecklistItemMoveRequest{
+		AfterID: items[0].ID,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, checklistUrl+"/"+last.ID+"/move", strings.NewReader(string(moveJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	request = testhelper.GetHTTPRequest(http.MethodGet, checklistUrl, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err = io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	err = json.Unmarshal(body, &items)
+	assert.Nil(t, err)
+
+	assert.Equal(t, last.ID, items[0].ID)
+}
+
+func TestUpdateChecklistItemSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	checklistUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/checklist"
+
+	request := testhelper.GetHTTPRequest(http.MethodGet, checklistUrl, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var items []models.ChecklistItemResponse
+	err = json.Unmarshal(body, &items)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, items)
+
+	// toggle the item
+	reqBody := models.ChecklistItemRequest{
+		Text:     items[0].Text,
+		Done:     !items[0].Done,
+		Assignee: "frontend",
+	}
+
+	itemJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPut, checklistUrl+"/"+items[0].ID, strings.NewReader(string(itemJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ChecklistItemResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, reqBody.Done, responseBody.Done)
+	assert.Equal(t, reqBody.Assignee, responseBody.Assignee)
+	assert.Equal(t, items[0].Rank, responseBody.Rank)
+}
+
+func TestDeleteChecklistItemSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	checklistUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/checklist"
+
+	request := testhelper.GetHTTPRequest(http.MethodGet, checklistUrl, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var items []models.ChecklistItemResponse
+	err = json.Unmarshal(body, &items)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, items)
+
+	request = testhelper.GetHTTPRequest(http.MethodDelete, checklistUrl+"/"+items[0].ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ChecklistItemDeleteResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "success", responseBody.Message)
+
+	// the item is gone
+	request = testhelper.GetHTTPRequest(http.MethodDelete, checklistUrl+"/"+items[0].ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+}
@@ -0,0 +1,271 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCommentSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	commentsUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/comments"
+
+	// start a thread
+	threadJson, err := json.Marshal(models.CommentCreateRequest{
+		Body: "Should this ticket also cover the mobile layout?",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, commentsUrl, strings.NewReader(string(threadJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var thread models.CommentResponse
+	err = json.Unmarshal(body, &thread)
+	assert.Nil(t, err)
+
+	assert.Nil(t, thread.ParentID)
+	assert.Equal(t, testhelper.TEST_USER.ID, thread.Author.ID)
+
+	// reply to the thread
+	replyJson, err := json.Marshal(models.CommentCreateRequest{
+		Body:     "Yes, the mobile layout is part of it.",
+		ParentID: thread.ID,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, commentsUrl, strings.NewReader(string(replyJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var reply models.CommentResponse
+	err = json.Unmarshal(body, &reply)
+	assert.Nil(t, err)
+
+	assert.Equal(t, thread.ID, *reply.ParentID)
+
+	// the reply is listed in its thread
+	request = testhelper.GetHTTPRequest(http.MethodGet, commentsUrl, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var comments []models.CommentResponse
+	err = json.Unmarshal(body, &comments)
+	assert.Nil(t, err)
+
+	assert.Len(t, comments, 1)
+	assert.Equal(t, thread.ID, comments[0].ID)
+	assert.Len(t, comments[0].Replies, 1)
+	assert.Equal(t, reply.ID, comments[0].Replies[0].ID)
+}
+
+func TestCreateCommentFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	commentsUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/comments"
+
+	// failed because of validation
+	commentJson, err := json.Marshal(models.CommentCreateRequest{
+		Body: "",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, commentsUrl, strings.NewReader(string(commentJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var validationResponseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &validationResponseBody)
+	assert.Nil(t, err)
+
+	// last validation ends with a dot
+	assert.Len(t, validationResponseBody.Message, 1)
+	assert.Equal(t, "body: is required.", validationResponseBody.Message[0])
+
+	// failed because replies can't be replied to
+	request = testhelper.GetHTTPRequest(http.MethodGet, commentsUrl, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err = io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var comments []models.CommentResponse
+	err = json.Unmarshal(body, &comments)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, comments)
+	assert.NotEmpty(t, comments[0].Replies)
+
+	commentJson, err = json.Marshal(models.CommentCreateRequest{
+		Body:     "Replying to a reply",
+		ParentID: comments[0].Replies[0].ID,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, commentsUrl, strings.NewReader(string(commentJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "parent_id must be a comment of the ticket that is not a reply", responseBody.Message)
+}
+
+func TestUpdateCommentSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	commentsUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/comments"
+
+	commentJson, err := json.Marshal(models.CommentCreateRequest{
+		Body: "Comment with a typo",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, commentsUrl, strings.NewReader(string(commentJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var comment models.CommentResponse
+	err = json.Unmarshal(body, &comment)
+	assert.Nil(t, err)
+
+	assert.Nil(t, comment.EditedAt)
+
+	// edit the comment
+	reqBody := models.CommentUpdateRequest{
+		Body: "Comment without a typo",
+	}
+
+	commentJson, err = json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPut, commentsUrl+"/"+comment.ID, strings.NewReader(string(commentJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.CommentResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, reqBody.Body, responseBody.Body)
+	assert.NotNil(t, responseBody.EditedAt)
+}
+
+func TestDeleteCommentSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	commentsUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/comments"
+
+	request := testhelper.GetHTTPRequest(http.MethodGet, commentsUrl, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var comments []models.CommentResponse
+	err = json.Unmarshal(body, &comments)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, comments)
+
+	// deleting a thread deletes its replies too
+	request = testhelper.GetHTTPRequest(http.MethodDelete, commentsUrl+"/"+comments[0].ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.CommentDeleteResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "success", responseBody.Message)
+
+	for _, reply := range comments[0].Replies {
+		request = testhelper.GetHTTPRequest(http.MethodDelete, commentsUrl+"/"+reply.ID, nil, token.AccessToken)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+	}
+}
@@ -0,0 +1,205 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvitationSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	workspace := createTestWorkspace(t, router, token.AccessToken)
+
+	// new accounts accept the invitation when they are created
+	invitation := createTestInvitation(t, router, token.AccessToken, workspace.ID, "invitee@example.com", models.WORKSPACE_ROLE_MEMBER, http.StatusCreated)
+	assert.NotEmpty(t, invitation.Token)
+
+	// the token is only shown once
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/iam/v1/invitations?workspace_id="+workspace.ID, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var invitations []models.InvitationResponse
+	err = json.Unmarshal(body, &invitations)
+	assert.Nil(t, err)
+
+	assert.Len(t, invitations, 1)
+	assert.Equal(t, invitation.ID, invitations[0].ID)
+	assert.Empty(t, invitations[0].Token)
+
+	// resending replaces the token
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/iam/v1/invitations/"+invitation.ID+"/resend", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var resent models.InvitationResponse
+	err = json.Unmarshal(body, &resent)
+	assert.Nil(t, err)
+
+	assert.NotEmpty(t, resent.Token)
+	assert.NotEqual(t, invitation.Token, resent.Token)
+
+	userJson, err := json.Marshal(models.UserCreateRequest{
+		Email:           "invitee@example.com",
+		FirstName:       "Invited",
+		LastName:        "User",
+		Password:        "invitedUser@123",
+		InvitationToken: resent.Token,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/iam/v1/users", strings.NewReader(string(userJson)), "")
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	// existing accounts accept the invitation themselves
+	_, inviteeToken := createWorkspaceTestUser(t, router, "existing-invitee@example.com")
+
+	invitation = createTestInvitation(t, router, token.AccessToken, workspace.ID, "existing-invitee@example.com", models.WORKSPACE_ROLE_VIEWER, http.StatusCreated)
+
+	acceptInvitation(t, router, inviteeToken, invitation.Token, http.StatusOK)
+
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/workspaces/"+workspace.ID, nil, inviteeToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var inviteeWorkspace models.WorkspaceResponse
+	err = json.Unmarshal(body, &inviteeWorkspace)
+	assert.Nil(t, err)
+
+	assert.Equal(t, models.WORKSPACE_ROLE_VIEWER, inviteeWorkspace.Role)
+
+	// accepted invitations are not pending anymore
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/iam/v1/invitations?workspace_id="+workspace.ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	err = json.Unmarshal(body, &invitations)
+	assert.Nil(t, err)
+
+	assert.Len(t, invitations, 0)
+}
+
+func TestInvitationFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	workspace := createTestWorkspace(t, router, token.AccessToken)
+
+	// members are not invited again
+	createTestInvitation(t, router, token.AccessToken, workspace.ID, testhelper.TEST_USER.Email, models.WORKSPACE_ROLE_MEMBER, http.StatusBadRequest)
+
+	_, inviteeToken := createWorkspaceTestUser(t, router, "invited-once@example.com")
+	_, strangerToken := createWorkspaceTestUser(t, router, "stranger@example.com")
+
+	invitation := createTestInvitation(t, router, token.AccessToken, workspace.ID, "invited-once@example.com", models.WORKSPACE_ROLE_MEMBER, http.StatusCreated)
+
+	// invitations can only be accepted by the email they were sent to
+	acceptInvitation(t, router, strangerToken, invitation.Token, http.StatusForbidden)
+
+	// tokens are single-use
+	acceptInvitation(t, router, inviteeToken, invitation.Token, http.StatusOK)
+	acceptInvitation(t, router, inviteeToken, invitation.Token, http.StatusNotFound)
+
+	// revoked invitations can't be accepted
+	invitation = createTestInvitation(t, router, token.AccessToken, workspace.ID, "stranger@example.com", models.WORKSPACE_ROLE_MEMBER, http.StatusCreated)
+
+	request := testhelper.GetHTTPRequest(http.MethodDelete, "/iam/v1/invitations/"+invitation.ID, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	acceptInvitation(t, router, strangerToken, invitation.Token, http.StatusNotFound)
+
+	// members can't invite
+	createTestInvitation(t, router, inviteeToken, workspace.ID, "someone@example.com", models.WORKSPACE_ROLE_VIEWER, http.StatusForbidden)
+}
+
+// helpers
+func createTestInvitation(t *testing.T, router *gin.Engine, accessToken string, workspaceId string, email string, role string, expectedStatus int) models.InvitationResponse {
+	invitationJson, err := json.Marshal(models.InvitationCreateRequest{
+		WorkspaceID: workspaceId,
+		Email:       email,
+		Role:        role,
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/iam/v1/invitations", strings.NewReader(string(invitationJson)), accessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, expectedStatus, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var invitation models.InvitationResponse
+	json.Unmarshal(body, &invitation)
+
+	return invitation
+}
+
+func acceptInvitation(t *testing.T, router *gin.Engine, accessToken string, invitationToken string, expectedStatus int) {
+	acceptJson, err := json.Marshal(models.InvitationAcceptRequest{Token: invitationToken})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/iam/v1/invitations/accept", strings.NewReader(string(acceptJson)), accessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, expectedStatus, response.StatusCode)
+}
@@ -0,0 +1,226 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateLabelSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	reqBody := models.LabelRequest{
+		Name:  "bug",
+		Color: "#ef4444",
+	}
+
+	labelJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/labels", strings.NewReader(string(labelJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.LabelResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, testhelper.TEST_BOARD.ID, responseBody.BoardID)
+	assert.Equal(t, reqBody.Name, responseBody.Name)
+	assert.Equal(t, reqBody.Color, responseBody.Color)
+}
+
+func TestCreateLabelFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// failed because of validation
+	labelJson, err := json.Marshal(models.LabelRequest{
+		Name:  "",
+		Color: "red",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/labels", strings.NewReader(string(labelJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var validationResponseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &validationResponseBody)
+	assert.Nil(t, err)
+
+	// validation key is ordered alphabetically
+	// last validation ends with a dot
+	assert.Len(t, validationResponseBody.Message, 2)
+	assert.Equal(t, "color: must be a hex color such as #22c55e", validationResponseBody.Message[0])
+	assert.Equal(t, "name: is required.", validationResponseBody.Message[1])
+
+	// failed because the name is already used on the board
+	labelJson, err = json.Marshal(models.LabelRequest{
+		Name:  "bug",
+		Color: "#f97316",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/labels", strings.NewReader(string(labelJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "label name is already used", responseBody.Message)
+}
+
+func TestAttachTicketLabelSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// get the label created before
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/labels", nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var labels []models.LabelResponse
+	err = json.Unmarshal(body, &labels)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, labels)
+
+	// attach the label
+	request = testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/labels/"+labels[0].ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	assert.Len(t, ticket.Labels, 1)
+	assert.Equal(t, labels[0].ID, ticket.Labels[0].ID)
+
+	// only tickets with the label are listed
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets?label="+labels[0].Name, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticketList models.TicketListResponse
+	err = json.Unmarshal(body, &ticketList)
+	assert.Nil(t, err)
+
+	assert.Len(t, ticketList.Data, 1)
+	assert.Equal(t, testhelper.TEST_TICKET.ID, ticketList.Data[0].ID)
+}
+
+func TestAttachTicketLabelFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// create a label on another board
+	boardJson, err := json.Marshal(models.BoardCreateRequest{Name: "Label Board"})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var board models.BoardResponse
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	labelJson, err := json.Marshal(models.LabelRequest{Name: "bug", Color: "#ef4444"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/labels", strings.NewReader(string(labelJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var label models.LabelResponse
+	err = json.Unmarshal(body, &label)
+	assert.Nil(t, err)
+
+	// failed because the label belongs to another board
+	request = testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/labels/"+label.ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "label must belong to the board of the ticket", responseBody.Message)
+}
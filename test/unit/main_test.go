@@ -22,6 +22,22 @@ func TestMain(m *testing.M) {
 		panic("[Error] failed to delete all test tickets before running test due to: " + err.Error())
 	}
 
+	if err := testhelper.DeleteAllTestBoards(D); err != nil {
+		panic("[Error] failed to delete all test boards before running test due to: " + err.Error())
+	}
+
+	if err := testhelper.DeleteAllTestTeams(D); err != nil {
+		panic("[Error] failed to delete all test teams before running test due to: " + err.Error())
+	}
+
+	if err := testhelper.DeleteAllTestSavedViews(D); err != nil {
+		panic("[Error] failed to delete all test saved views before running test due to: " + err.Error())
+	}
+
+	if err := testhelper.DeleteAllTestWorkspaces(D); err != nil {
+		panic("[Error] failed to delete all test workspaces before running test due to: " + err.Error())
+	}
+
 	if err := testhelper.DeleteAllTestUsers(D); err != nil {
 		panic("[Error] failed to delete all test users before running test due to: " + err.Error())
 	}
@@ -31,6 +47,10 @@ func TestMain(m *testing.M) {
 		panic("[Error] failed to create test user due to: " + err.Error())
 	}
 
+	if err := testhelper.CreateTestBoard(D); err != nil {
+		panic("[Error] failed to create test board due to: " + err.Error())
+	}
+
 	if err := testhelper.CreateTestTicket(D); err != nil {
 		panic("[Error] failed to create test ticket due to: " + err.Error())
 	}
@@ -41,6 +61,22 @@ func TestMain(m *testing.M) {
 		panic("[Error] failed to delete all test tickets after running test due to: " + err.Error())
 	}
 
+	if err := testhelper.DeleteAllTestBoards(D); err != nil {
+		panic("[Error] failed to delete all test boards after running test due to: " + err.Error())
+	}
+
+	if err := testhelper.DeleteAllTestTeams(D); err != nil {
+		panic("[Error] failed to delete all test teams after running test due to: " + err.Error())
+	}
+
+	if err := testhelper.DeleteAllTestSavedViews(D); err != nil {
+		panic("[Error] failed to delete all test saved views after running test due to: " + err.Error())
+	}
+
+	if err := testhelper.DeleteAllTestWorkspaces(D); err != nil {
+		panic("[Error] failed to delete all test workspaces after running test due to: " + err.Error())
+	}
+
 	if err := testhelper.DeleteAllTestUsers(D); err != nil {
 		panic("[Error] failed to delete all test users after running test due to: " + err.Error())
 	}
@@ -0,0 +1,163 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMentionedHandles(t *testing.T) {
+	handles := models.MentionedHandles("@Jane@Example.com please pair with @john@example.co.uk. cc @jane@example.com, not jane@example.com")
+	assert.Equal(t, []string{"jane@example.com", "john@example.co.uk"}, handles)
+
+	// usernames are too short, too long or the start of an email without a domain
+	handles = models.MentionedHandles("thanks @Jane_Doe and @bob. cc @jo and @jane@nodomain")
+	assert.Equal(t, []string{"jane_doe", "bob"}, handles)
+
+	handles = models.NewMentionedHandles("ask @jane@example.com", "ask @jane@example.com and @john_doe")
+	assert.Equal(t, []string{"john_doe"}, handles)
+}
+
+func TestMentionSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	mentioned, _ := createWorkspaceTestUser(t, router, "mentioned@example.com")
+	board := createAssigneeBoard(t, router, token.AccessToken, mentioned)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:       "Mentioning ticket",
+		Description: "please review @mentioned@example.com",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	var count int64
+	err = D.DB.Model(&models.Mention{}).Where("ticket_id = ? AND user_id = ? AND comment_id IS NULL", ticket.ID, mentioned.ID).Count(&count).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// comments mention users the same way
+	commentJson, err := json.Marshal(models.CommentCreateRequest{Body: "@mentioned@example.com can you take over?"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+ticket.ID+"/comments", strings.NewReader(string(commentJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var comment models.CommentResponse
+	err = json.Unmarshal(body, &comment)
+	assert.Nil(t, err)
+
+	err = D.DB.Model(&models.Mention{}).Where("comment_id = ? AND user_id = ?", comment.ID, mentioned.ID).Count(&count).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// users are also mentioned by their username, which is derived from their email
+	assert.Equal(t, "mentioned", mentioned.Username)
+
+	commentJson, err = json.Marshal(models.CommentCreateRequest{Body: "thanks @mentioned"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+ticket.ID+"/comments", strings.NewReader(string(commentJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	err = json.Unmarshal(body, &comment)
+	assert.Nil(t, err)
+
+	err = D.DB.Model(&models.Mention{}).Where("comment_id = ? AND user_id = ?", comment.ID, mentioned.ID).Count(&count).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// mentions that were already in the description are not recorded again
+	request = testhelper.GetHTTPRequest(http.MethodPatch, "/kanban/v1/tickets/"+ticket.ID, strings.NewReader(`{"description": "please review again @mentioned@example.com"}`), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	err = D.DB.Model(&models.Mention{}).Where("ticket_id = ? AND comment_id IS NULL", ticket.ID).Count(&count).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestMentionFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	member, _ := createWorkspaceTestUser(t, router, "mention-member@example.com")
+	createWorkspaceTestUser(t, router, "mention-outsider@example.com")
+	board := createAssigneeBoard(t, router, token.AccessToken, member)
+
+	// users that can't see the ticket can't be mentioned
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:       "Mentioning an outsider",
+		Description: "@mention-outsider@example.com should know",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// neither can unknown users, by their email or by their username
+	for _, body := range []string{"@nobody@example.com should know", "@nobody should know", "@mention_outsider should know"} {
+		commentJson, err := json.Marshal(models.CommentCreateRequest{Body: body})
+		assert.Nil(t, err)
+
+		request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/comments", strings.NewReader(string(commentJson)), token.AccessToken)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response = recorder.Result()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	}
+}
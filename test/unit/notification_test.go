@@ -0,0 +1,235 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	recipient, recipientToken := createWorkspaceTestUser(t, router, "notified@example.com")
+	board := createAssigneeBoard(t, router, token.AccessToken, recipient)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:       "Notifying ticket",
+		AssigneeIDs: models.StringArray{recipient.ID},
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	// moving the ticket notifies its assignees
+	moveJson, err := json.Marshal(models.TicketMoveRequest{Status: "doing"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+ticket.ID+"/move", strings.NewReader(string(moveJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	notifications := getTestNotifications(t, router, recipientToken)
+	assert.Equal(t, int64(2), notifications.UnreadCount)
+	assert.Len(t, notifications.Data, 2)
+
+	// newest first
+	assert.Equal(t, models.NOTIFICATION_TYPE_STATUS_CHANGED, notifications.Data[0].Type)
+	assert.Equal(t, "doing", notifications.Data[0].Ticket.Status)
+	assert.Equal(t, models.NOTIFICATION_TYPE_ASSIGNED, notifications.Data[1].Type)
+	assert.Equal(t, testhelper.TEST_USER.ID, notifications.Data[1].Actor.ID)
+
+	// users aren't notified of what they did themselves
+	assert.Len(t, getTestNotifications(t, router, token.AccessToken).Data, 0)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/iam/v1/notifications/"+notifications.Data[0].ID+"/read", nil, recipientToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	assert.Equal(t, int64(1), getTestNotifications(t, router, recipientToken).UnreadCount)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/iam/v1/notifications/read-all", nil, recipientToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var readAll models.NotificationReadAllResponse
+	err = json.Unmarshal(body, &readAll)
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(1), readAll.Updated)
+	assert.Equal(t, int64(0), getTestNotifications(t, router, recipientToken).UnreadCount)
+}
+
+func TestNotificationFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	recipient, recipientToken := createWorkspaceTestUser(t, router, "notified-once@example.com")
+	board := createAssigneeBoard(t, router, token.AccessToken, recipient)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:       "Notifying someone else",
+		AssigneeIDs: models.StringArray{recipient.ID},
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	notifications := getTestNotifications(t, router, recipientToken)
+	assert.Len(t, notifications.Data, 1)
+
+	// notifications of other users can't be marked as read
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/iam/v1/notifications/"+notifications.Data[0].ID+"/read", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	// invalid page size
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/iam/v1/notifications?limit=1000", nil, recipientToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestPushNotificationSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	recipient, recipientToken := createWorkspaceTestUser(t, router, "pushed@example.com")
+	board := createAssigneeBoard(t, router, token.AccessToken, recipient)
+
+	recipientConn := dialTestWebSocket(t, server, recipientToken)
+	defer recipientConn.Close()
+
+	actorConn := dialTestWebSocket(t, server, token.AccessToken)
+	defer actorConn.Close()
+
+	// clients without an access token can't connect, so they never get notifications
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if conn != nil {
+		conn.Close()
+	}
+	assert.NotNil(t, err)
+
+	// the clients are registered right after the handshake
+	time.Sleep(100 * time.Millisecond)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:       "Pushed ticket",
+		AssigneeIDs: models.StringArray{recipient.ID},
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	// the recipient gets the notification next to the ticket event
+	var notification *models.NotificationResponse
+	recipientConn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for notification == nil {
+		var message models.WSMessage
+		if err := recipientConn.ReadJSON(&message); !assert.Nil(t, err) {
+			break
+		}
+		if message.Event == "notification.created" {
+			notification = message.Notification
+		}
+	}
+	if assert.NotNil(t, notification) {
+		assert.Equal(t, models.NOTIFICATION_TYPE_ASSIGNED, notification.Type)
+	}
+
+	// the notification is only pushed to its recipient
+	actorConn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	for {
+		var message models.WSMessage
+		if err := actorConn.ReadJSON(&message); err != nil {
+			break
+		}
+		assert.NotEqual(t, "notification.created", message.Event)
+	}
+}
+
+// helpers
+func getTestNotifications(t *testing.T, router *gin.Engine, accessToken string) models.NotificationListResponse {
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/iam/v1/notifications", nil, accessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var notifications models.NotificationListResponse
+	err = json.Unmarshal(body, &notifications)
+	assert.Nil(t, err)
+
+	return notifications
+}
@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	queryhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuerySuccess(t *testing.T) {
+	terms, err := queryhelper.Parse(`status:todo,doing assignee:backend -label:"won't fix" updated>-7d "login bug" crash`)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []queryhelper.Term{
+		{Field: "status", Operator: ":", Values: []string{"todo", "doing"}},
+		{Field: "assignee", Operator: ":", Values: []string{"backend"}},
+		{Negated: true, Field: "label", Operator: ":", Values: []string{"won't fix"}},
+		{Field: "updated", Operator: ">", Values: []string{"-7d"}},
+		{Values: []string{"login bug"}, Phrase: true},
+		{Values: []string{"crash"}},
+	}, terms)
+
+	// the longest operator wins
+	terms, err = queryhelper.Parse("priority>=high")
+	assert.Nil(t, err)
+	assert.Equal(t, []queryhelper.Term{{Field: "priority", Operator: ">=", Values: []string{"high"}}}, terms)
+
+	// an empty query has no terms
+	terms, err = queryhelper.Parse("   ")
+	assert.Nil(t, err)
+	assert.Empty(t, terms)
+}
+
+func TestParseQueryFailed(t *testing.T) {
+	_, err := queryhelper.Parse(`status:todo "login bug`)
+	assert.EqualError(t, err, "unterminated quote at position 12")
+
+	_, err = queryhelper.Parse("status: todo")
+	assert.EqualError(t, err, `missing value for "status" at position 0`)
+}
+
+func TestParseQueryTimeSuccess(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+
+	value, isDay, err := queryhelper.ParseTime("-7d", now)
+	assert.Nil(t, err)
+	assert.False(t, isDay)
+	assert.Equal(t, time.Date(2024, 3, 8, 10, 30, 0, 0, time.UTC), value)
+
+	value, isDay, err = queryhelper.ParseTime("today", now)
+	assert.Nil(t, err)
+	assert.True(t, isDay)
+	assert.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), value)
+
+	value, isDay, err = queryhelper.ParseTime("2024-01-31", now)
+	assert.Nil(t, err)
+	assert.True(t, isDay)
+	assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), value)
+
+	_, _, err = queryhelper.ParseTime("last week", now)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,48 @@
+package unit
+
+import (
+	"testing"
+
+	rankhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/rank"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankBetweenSuccess(t *testing.T) {
+	// first rank of an empty column
+	first, err := rankhelper.Between("", "")
+	assert.Nil(t, err)
+
+	// append at the bottom
+	last, err := rankhelper.After(first)
+	assert.Nil(t, err)
+	assert.Less(t, first, last)
+
+	// prepend at the top
+	top, err := rankhelper.Before(first)
+	assert.Nil(t, err)
+	assert.Less(t, top, first)
+
+	// insert between neighbours repeatedly without renumbering them
+	prev, next := first, last
+	for i := 0; i < 100; i++ {
+		rank, err := rankhelper.Between(prev, next)
+		assert.Nil(t, err)
+		assert.Less(t, prev, rank)
+		assert.Less(t, rank, next)
+		next = rank
+	}
+}
+
+func TestRankBetweenFailed(t *testing.T) {
+	// prev must be lower than next
+	_, err := rankhelper.Between("b", "a")
+	assert.ErrorIs(t, err, rankhelper.ErrInvalidOrder)
+
+	// ranks cannot end with the smallest digit
+	_, err = rankhelper.Between("a0", "")
+	assert.ErrorIs(t, err, rankhelper.ErrInvalidRank)
+
+	// ranks only contain lowercase letters and numbers
+	_, err = rankhelper.Between("", "A")
+	assert.ErrorIs(t, err, rankhelper.ErrInvalidRank)
+}
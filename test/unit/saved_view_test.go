@@ -0,0 +1,125 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSavedViewSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	reqBody := models.SavedViewRequest{
+		Name:  "Backend work",
+		Query: `status:todo,doing assignee:backend -label:wontfix updated>-7d`,
+	}
+
+	viewJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/views", strings.NewReader(string(viewJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.SavedViewResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, reqBody.Name, responseBody.Name)
+	assert.Equal(t, reqBody.Query, responseBody.Query)
+
+	// the view is listed
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/views", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var listResponseBody []models.SavedViewResponse
+	err = json.Unmarshal(body, &listResponseBody)
+	assert.Nil(t, err)
+
+	assert.Contains(t, listResponseBody, responseBody)
+}
+
+func TestCreateSavedViewFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// failed because of validation
+	viewJson, err := json.Marshal(models.SavedViewRequest{
+		Name:  "",
+		Query: `status:todo "login bug`,
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/views", strings.NewReader(string(viewJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	// validation key is ordered alphabetically
+	// last validation ends with a dot
+	assert.Len(t, responseBody.Message, 2)
+	assert.Equal(t, "name: is required", responseBody.Message[0])
+	assert.Equal(t, "query: unterminated quote at position 12.", responseBody.Message[1])
+
+	// failed because the name is already used
+	viewJson, err = json.Marshal(models.SavedViewRequest{
+		Name:  "Backend work",
+		Query: "assignee:backend",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/views", strings.NewReader(string(viewJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var nameResponseBody models.ErrorMessage
+	err = json.Unmarshal(body, &nameResponseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "view name is already used", nameResponseBody.Message)
+}
@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTicketsSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:       "Login issue",
+		Description: "The login page crashes when the password contains emojis or <script>alert(1)</script>",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	// stemming matches "crashing" with "crashes" and "emoji" with "emojis"
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/search?q="+url.QueryEscape("crashing emoji -safari"), nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.SearchResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.NotEmpty(t, responseBody.Data)
+	assert.Equal(t, ticket.ID, responseBody.Data[0].Ticket.ID)
+	assert.Greater(t, responseBody.Data[0].Score, 0.0)
+	assert.Contains(t, responseBody.Data[0].Highlights.Description, "<mark>crashes</mark>")
+
+	// only the matches are markup, the text of the ticket is escaped
+	assert.NotContains(t, responseBody.Data[0].Highlights.Description, "<script>")
+	assert.Contains(t, responseBody.Data[0].Highlights.Description, "&lt;script&gt;")
+
+	// excluded words remove the ticket from the results
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/search?q="+url.QueryEscape("crashing -emoji"), nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var excludedResponseBody models.SearchResponse
+	err = json.Unmarshal(body, &excludedResponseBody)
+	assert.Nil(t, err)
+
+	for _, result := range excludedResponseBody.Data {
+		assert.NotEqual(t, ticket.ID, result.Ticket.ID)
+	}
+
+	// clean up
+	request = testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/tickets/"+ticket.ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestSearchTicketsFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// failed because of validation
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/search?q=%20", nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// failed because the board doesn't exist
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/search?q=login&board_id=wrongboardid", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
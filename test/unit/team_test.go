@@ -0,0 +1,302 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTeamSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	reqBody := models.TeamCreateRequest{
+		Key:  "mobile",
+		Name: "Mobile",
+	}
+
+	teamJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/teams", strings.NewReader(string(teamJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TeamResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, reqBody.Key, responseBody.Key)
+	assert.Equal(t, reqBody.Name, responseBody.Name)
+
+	// tickets can now be assigned to the new team
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:     "Ticket for the mobile team",
+		Assignees: []string{"mobile"},
+		Status:    "todo",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+}
+
+func TestCreateTeamFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// failed because of validation
+	reqBody := models.TeamCreateRequest{
+		Key:  "Dev Ops",
+		Name: "",
+	}
+
+	teamJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/teams", strings.NewReader(string(teamJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	// validation key is ordered alphabetically
+	// last validation ends with a dot
+	assert.Len(t, responseBody.Message, 2)
+	assert.Equal(t, "key: must start with a lowercase letter and only contain lowercase letters, numbers, \"-\" or \"_\"", responseBody.Message[0])
+	assert.Equal(t, "name: is required.", responseBody.Message[1])
+
+	// failed because the key is already used
+	teamJson, err = json.Marshal(models.TeamCreateRequest{
+		Key:  "frontend",
+		Name: "Frontend",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/teams", strings.NewReader(string(teamJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var keyResponseBody models.ErrorMessage
+	err = json.Unmarshal(body, &keyResponseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "team key is already used", keyResponseBody.Message)
+}
+
+func TestDeleteTeamFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// the test ticket is assigned to the frontend team of the workspace of the test board
+	var team models.Team
+	boardWorkspace := D.DB.Model(&models.Board{}).Select("workspace_id").Where("id = ?", testhelper.TEST_BOARD.ID)
+	err = D.DB.Where("workspace_id = (?) AND key = ?", boardWorkspace, "frontend").First(&team).Error
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/teams/"+team.ID, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "team \"frontend\" is still assigned to tickets and cannot be deleted", responseBody.Message)
+}
+
+func TestWorkspaceTeamSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	member, memberToken := createWorkspaceTestUser(t, router, "team-member@example.com")
+	board := createAssigneeBoard(t, router, token.AccessToken, member)
+	teamsUrl := "/kanban/v1/workspaces/" + board.WorkspaceID + "/teams"
+
+	// admins create the teams of the workspace
+	teamJson, err := json.Marshal(models.TeamCreateRequest{
+		Key:  "qa",
+		Name: "QA",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, teamsUrl, strings.NewReader(string(teamJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var team models.TeamResponse
+	err = json.Unmarshal(body, &team)
+	assert.Nil(t, err)
+
+	assert.Equal(t, board.WorkspaceID, team.WorkspaceID)
+
+	// members see the default teams and the new team
+	request = testhelper.GetHTTPRequest(http.MethodGet, teamsUrl, nil, memberToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var teams []models.TeamResponse
+	err = json.Unmarshal(body, &teams)
+	assert.Nil(t, err)
+
+	teamKeys := []string{}
+	for _, team := range teams {
+		teamKeys = append(teamKeys, team.Key)
+	}
+	assert.ElementsMatch(t, []string{"frontend", "backend", "design", "qa"}, teamKeys)
+
+	// tickets of the workspace can be assigned to its teams, but not to the teams of other workspaces
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:     "Ticket for the QA team",
+		Assignees: []string{"qa"},
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), memberToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusCreated, recorder.Result().StatusCode)
+
+	ticketJson, err = json.Marshal(models.TicketCreateRequest{
+		Title:     "Ticket for the mobile team",
+		Assignees: []string{"mobile"},
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), memberToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+}
+
+func TestWorkspaceTeamFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	member, memberToken := createWorkspaceTestUser(t, router, "team-non-admin@example.com")
+	_, outsiderToken := createWorkspaceTestUser(t, router, "team-outsider@example.com")
+	board := createAssigneeBoard(t, router, token.AccessToken, member)
+	teamsUrl := "/kanban/v1/workspaces/" + board.WorkspaceID + "/teams"
+
+	// failed because members can't create teams
+	teamJson, err := json.Marshal(models.TeamCreateRequest{
+		Key:  "support",
+		Name: "Support",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, teamsUrl, strings.NewReader(string(teamJson)), memberToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+
+	// failed because members can't rename or delete teams
+	var team models.Team
+	err = D.DB.Where("workspace_id = ? AND key = ?", board.WorkspaceID, "design").First(&team).Error
+	assert.Nil(t, err)
+
+	updateJson, err := json.Marshal(models.TeamUpdateRequest{Name: "UX"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/teams/"+team.ID, strings.NewReader(string(updateJson)), memberToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+
+	request = testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/teams/"+team.ID, nil, memberToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+
+	// failed because outsiders don't see the teams of the workspace
+	request = testhelper.GetHTTPRequest(http.MethodGet, teamsUrl, nil, outsiderToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+
+	request = testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/teams/"+team.ID, strings.NewReader(string(updateJson)), outsiderToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+}
@@ -0,0 +1,133 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTicketHistorySuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// create a ticket
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:  "Ticket with history",
+		Status: "todo",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	// move the ticket to another column
+	moveJson, err := json.Marshal(models.TicketMoveRequest{Status: "doing"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+ticket.ID+"/move", strings.NewReader(string(moveJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// newest events come first
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+ticket.ID+"/history", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TicketEventListResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.NotEmpty(t, responseBody.Data)
+	assert.Equal(t, models.TICKET_EVENT_MOVED, responseBody.Data[0].Action)
+	assert.Equal(t, models.TICKET_EVENT_CREATED, responseBody.Data[len(responseBody.Data)-1].Action)
+
+	var statusEvent *models.TicketEventResponse
+	for i, event := range responseBody.Data {
+		if event.Action == models.TICKET_EVENT_MOVED && event.Field == "status" {
+			statusEvent = &responseBody.Data[i]
+		}
+	}
+
+	assert.NotNil(t, statusEvent)
+	assert.JSONEq(t, `"todo"`, string(statusEvent.OldValue))
+	assert.JSONEq(t, `"doing"`, string(statusEvent.NewValue))
+	assert.Equal(t, testhelper.TEST_USER.ID, statusEvent.Actor.ID)
+}
+
+func TestGetTicketHistoryFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// failed because of validation
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/history?limit=1000", nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var validationResponseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &validationResponseBody)
+	assert.Nil(t, err)
+
+	// last validation ends with a dot
+	assert.Len(t, validationResponseBody.Message, 1)
+	assert.Equal(t, "limit: must be at most 100.", validationResponseBody.Message[0])
+
+	// failed because the ticket doesn't exist
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/wrongticketid/history", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "ticket not found", responseBody.Message)
+}
@@ -0,0 +1,337 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketKeySuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	boardJson, err := json.Marshal(models.BoardCreateRequest{
+		Name: "Sequence Board",
+		Key:  "SEQ",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var board models.BoardResponse
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "SEQ", board.Key)
+
+	// tickets are numbered in the order they are created
+	var tickets []models.TicketResponse
+	for _, title := range []string{"First ticket", "Second ticket"} {
+		ticketJson, err := json.Marshal(models.TicketCreateRequest{Title: title})
+		assert.Nil(t, err)
+
+		request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response = recorder.Result()
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+		body, err = io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		var ticket models.TicketResponse
+		err = json.Unmarshal(body, &ticket)
+		assert.Nil(t, err)
+
+		tickets = append(tickets, ticket)
+	}
+
+	assert.Equal(t, "SEQ-1", tickets[0].Key)
+	assert.Equal(t, "SEQ-2", tickets[1].Key)
+
+	// the key works wherever the ticket ID does, in any case
+	for _, key := range []string{"SEQ-2", "seq-2"} {
+		request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+key, nil, token.AccessToken)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response = recorder.Result()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		body, err = io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		var ticket models.TicketResponse
+		err = json.Unmarshal(body, &ticket)
+		assert.Nil(t, err)
+
+		assert.Equal(t, tickets[1].ID, ticket.ID)
+	}
+
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/SEQ-1/history", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// boards without a key get one derived from their name
+	boardJson, err = json.Marshal(models.BoardCreateRequest{Name: "Mobile App"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "MA", board.Key)
+}
+
+func TestBoardKeyConcurrentSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	boardJson, err := json.Marshal(models.BoardCreateRequest{Name: "Parallel Board"})
+	assert.Nil(t, err)
+
+	// boards created at the same time still get different keys
+	keys := make([]string, 5)
+	var wg sync.WaitGroup
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+			body, err := io.ReadAll(response.Body)
+			assert.Nil(t, err)
+
+			var board models.BoardResponse
+			err = json.Unmarshal(body, &board)
+			assert.Nil(t, err)
+
+			keys[i] = board.Key
+		}(i)
+	}
+	wg.Wait()
+
+	assert.ElementsMatch(t, []string{"PB", "PB2", "PB3", "PB4", "PB5"}, keys)
+}
+
+func TestTicketKeyFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// keys must be uppercase
+	boardJson, err := json.Marshal(models.BoardCreateRequest{
+		Name: "Invalid Key Board",
+		Key:  "kan",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// keys are unique among the boards of the user
+	for _, expectedStatus := range []int{http.StatusCreated, http.StatusBadRequest} {
+		boardJson, err = json.Marshal(models.BoardCreateRequest{
+			Name: "Duplicate Key Board",
+			Key:  "DUP",
+		})
+		assert.Nil(t, err)
+
+		request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response = recorder.Result()
+		assert.Equal(t, expectedStatus, response.StatusCode)
+	}
+
+	// unknown keys are not found
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/DUP-999", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestTicketKeyAmbiguousFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	member, memberToken := createWorkspaceTestUser(t, router, "key-member@example.com")
+
+	// board keys are only unique within a workspace, both tickets get the key CLASH-1
+	var tickets []models.TicketResponse
+	for i := 0; i < 2; i++ {
+		workspace := createTestWorkspace(t, router, token.AccessToken)
+
+		if i == 0 {
+			memberJson, err := json.Marshal(models.MembershipCreateRequest{
+				Email: member.Email,
+				Role:  models.WORKSPACE_ROLE_MEMBER,
+			})
+			assert.Nil(t, err)
+
+			request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/members", strings.NewReader(string(memberJson)), token.AccessToken)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			assert.Equal(t, http.StatusCreated, response.StatusCode)
+		}
+
+		boardJson, err := json.Marshal(models.BoardCreateRequest{
+			Name: "Clashing Board",
+			Key:  "CLASH",
+		})
+		assert.Nil(t, err)
+
+		request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response := recorder.Result()
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+		body, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		var board models.BoardResponse
+		err = json.Unmarshal(body, &board)
+		assert.Nil(t, err)
+
+		ticketJson, err := json.Marshal(models.TicketCreateRequest{Title: "Clashing ticket"})
+		assert.Nil(t, err)
+
+		request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response = recorder.Result()
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+		body, err = io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		var ticket models.TicketResponse
+		err = json.Unmarshal(body, &ticket)
+		assert.Nil(t, err)
+
+		assert.Equal(t, "CLASH-1", ticket.Key)
+		tickets = append(tickets, ticket)
+	}
+
+	// users that can see both tickets can't use the key
+	for _, key := range []string{"CLASH-1", "clash-1"} {
+		for _, path := range []string{"/kanban/v1/tickets/" + key, "/kanban/v1/tickets/" + key + "/history"} {
+			request := testhelper.GetHTTPRequest(http.MethodGet, path, nil, token.AccessToken)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			assert.Equal(t, http.StatusConflict, response.StatusCode)
+		}
+	}
+
+	// neither to reference the other ticket
+	linkJson, err := json.Marshal(models.TicketLinkRequest{
+		Type:     models.TICKET_LINK_RELATES_TO,
+		TicketID: "CLASH-1",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+tickets[0].ID+"/links", strings.NewReader(string(linkJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+
+	// the IDs still work
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+tickets[1].ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// users that only see one of the tickets get that one
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/CLASH-1", nil, memberToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	assert.Equal(t, tickets[0].ID, ticket.ID)
+}
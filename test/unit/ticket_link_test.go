@@ -0,0 +1,327 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTicketLinkSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// create the ticket that is blocked by the test ticket
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title: "Ticket waiting for the test ticket",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var blockedTicket models.TicketResponse
+	err = json.Unmarshal(body, &blockedTicket)
+	assert.Nil(t, err)
+
+	// link the tickets
+	linkJson, err := json.Marshal(models.TicketLinkRequest{
+		Type:     models.TICKET_LINK_BLOCKS,
+		TicketID: blockedTicket.ID,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/links", strings.NewReader(string(linkJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var link models.TicketLinkResponse
+	err = json.Unmarshal(body, &link)
+	assert.Nil(t, err)
+
+	assert.Equal(t, testhelper.TEST_TICKET.ID, link.TicketID)
+	assert.Equal(t, blockedTicket.ID, link.LinkedTicketID)
+
+	// the link is shown on the blocked ticket
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+blockedTicket.ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, ticket.Links)
+	assert.Len(t, ticket.Links.BlockedBy, 1)
+	assert.Equal(t, testhelper.TEST_TICKET.ID, ticket.Links.BlockedBy[0].ID)
+	assert.False(t, ticket.Links.BlockedBy[0].Done)
+}
+
+func TestCreateTicketLinkFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	linksUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/links"
+
+	// failed because of validation
+	linkJson, err := json.Marshal(models.TicketLinkRequest{
+		Type: "depends_on",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, linksUrl, strings.NewReader(string(linkJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var validationResponseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &validationResponseBody)
+	assert.Nil(t, err)
+
+	assert.Len(t, validationResponseBody.Message, 2)
+
+	// failed because a ticket can't be linked to itself
+	linkJson, err = json.Marshal(models.TicketLinkRequest{
+		Type:     models.TICKET_LINK_RELATES_TO,
+		TicketID: testhelper.TEST_TICKET.ID,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, linksUrl, strings.NewReader(string(linkJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+
+	// failed because the blocked ticket can't block its blocker
+	request = testhelper.GetHTTPRequest(http.MethodGet, linksUrl, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err = io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var links models.TicketLinksResponse
+	err = json.Unmarshal(body, &links)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, links.Blocks)
+
+	linkJson, err = json.Marshal(models.TicketLinkRequest{
+		Type:     models.TICKET_LINK_BLOCKS,
+		TicketID: testhelper.TEST_TICKET.ID,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+links.Blocks[0].ID+"/links", strings.NewReader(string(linkJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "link would create a cycle", responseBody.Message)
+
+	// failed because the user is only a viewer of the workspace of the linked ticket
+	_, ownerToken := createWorkspaceTestUser(t, router, "link-owner@example.com")
+	workspace := createTestWorkspace(t, router, ownerToken)
+
+	memberJson, err := json.Marshal(models.MembershipCreateRequest{
+		Email: testhelper.TEST_USER.Email,
+		Role:  models.WORKSPACE_ROLE_VIEWER,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/members", strings.NewReader(string(memberJson)), ownerToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusCreated, recorder.Result().StatusCode)
+
+	boardJson, err := json.Marshal(models.BoardCreateRequest{Name: "Read Only Board"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/boards", strings.NewReader(string(boardJson)), ownerToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err = io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var board models.BoardResponse
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{Title: "Read only ticket"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), ownerToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err = io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var readOnlyTicket models.TicketResponse
+	err = json.Unmarshal(body, &readOnlyTicket)
+	assert.Nil(t, err)
+
+	linkJson, err = json.Marshal(models.TicketLinkRequest{
+		Type:     models.TICKET_LINK_RELATES_TO,
+		TicketID: readOnlyTicket.ID,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, linksUrl, strings.NewReader(string(linkJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+}
+
+func TestMoveBlockedTicketFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/links", nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var links models.TicketLinksResponse
+	err = json.Unmarshal(body, &links)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, links.Blocks)
+
+	// the test ticket isn't done yet
+	moveJson, err := json.Marshal(models.TicketMoveRequest{
+		Status: "doing",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+links.Blocks[0].ID+"/move", strings.NewReader(string(moveJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TicketBlockedErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{testhelper.TEST_TICKET.ID}, responseBody.BlockedBy)
+}
+
+func TestDeleteTicketLinkSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	linksUrl := "/kanban/v1/tickets/" + testhelper.TEST_TICKET.ID + "/links"
+
+	request := testhelper.GetHTTPRequest(http.MethodGet, linksUrl, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	assert.Nil(t, err)
+
+	var links models.TicketLinksResponse
+	err = json.Unmarshal(body, &links)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, links.Blocks)
+
+	request = testhelper.GetHTTPRequest(http.MethodDelete, linksUrl+"/"+links.Blocks[0].LinkID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TicketLinkDeleteResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "success", responseBody.Message)
+
+	// the ticket isn't blocked anymore
+	moveJson, err := json.Marshal(models.TicketMoveRequest{
+		Status: "doing",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+links.Blocks[0].ID+"/move", strings.NewReader(string(moveJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+}
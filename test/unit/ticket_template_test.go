@@ -0,0 +1,172 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTicketFromTemplateSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	templateJson, err := json.Marshal(models.TicketTemplateRequest{
+		Name:         "Bug report",
+		TitlePattern: "[Bug] {title}",
+		Description:  "Steps to reproduce:\nExpected:\nActual:",
+		Assignees:    models.StringArray{"backend"},
+		Status:       "todo",
+		Priority:     "high",
+		Checklist:    models.StringArray{"Reproduce the bug", "Write a failing test", "Fix the bug"},
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/templates", strings.NewReader(string(templateJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var template models.TicketTemplateResponse
+	err = json.Unmarshal(body, &template)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Bug report", template.Name)
+	assert.Len(t, template.Checklist, 3)
+
+	// fields of the request win over the fields of the template
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:      "Login fails",
+		Priority:   "urgent",
+		TemplateID: template.ID,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "[Bug] Login fails", ticket.Title)
+	assert.Equal(t, "Steps to reproduce:\nExpected:\nActual:", ticket.Description)
+	assert.Equal(t, models.StringArray{"backend"}, ticket.Assignees)
+	assert.Equal(t, "todo", ticket.Status)
+	assert.Equal(t, "urgent", ticket.Priority)
+	assert.Equal(t, 3, ticket.Checklist.Total)
+
+	// the checklist keeps the order of the template
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+ticket.ID+"/checklist", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var checklist []models.ChecklistItemResponse
+	err = json.Unmarshal(body, &checklist)
+	assert.Nil(t, err)
+
+	assert.Len(t, checklist, 3)
+	for i, text := range template.Checklist {
+		assert.Equal(t, text, checklist[i].Text)
+	}
+}
+
+func TestCreateTicketFromTemplateFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// templates are validated against the workflow of the board
+	templateJson, err := json.Marshal(models.TicketTemplateRequest{
+		Name:   "Invalid template",
+		Status: "unknown",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/templates", strings.NewReader(string(templateJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// the filled in title is still validated
+	templateJson, err = json.Marshal(models.TicketTemplateRequest{
+		Name:         "Short title",
+		TitlePattern: "Bug",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/templates", strings.NewReader(string(templateJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var template models.TicketTemplateResponse
+	err = json.Unmarshal(body, &template)
+	assert.Nil(t, err)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{TemplateID: template.ID})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// unknown templates are not found
+	ticketJson, err = json.Marshal(models.TicketCreateRequest{
+		Title:      "Ticket from nowhere",
+		TemplateID: "unknown",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
@@ -5,8 +5,10 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
 	"github.com/Manuel-Leleuly/kanban-flow-go/models"
@@ -91,6 +93,262 @@ func TestCreateTicketFailed(t *testing.T) {
 	assert.Equal(t, "title: must have length between 8 and 50.", responseBody.Message[2])
 }
 
+func TestGetOverdueTicketListSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// create a ticket that is past its due date
+	dueAt := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	estimate := 5
+	reqBody := models.TicketCreateRequest{
+		Title:    "Overdue Test Ticket",
+		Status:   "doing",
+		DueAt:    &dueAt,
+		Priority: "urgent",
+		Estimate: &estimate,
+	}
+
+	ticketJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	assert.True(t, dueAt.Equal(*ticket.DueAt))
+	assert.Equal(t, reqBody.Priority, ticket.Priority)
+	assert.Equal(t, estimate, *ticket.Estimate)
+
+	// the ticket is overdue and has a high priority
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets?overdue=true&min_priority=high", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TicketListResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Len(t, responseBody.Data, 1)
+	assert.Equal(t, ticket.ID, responseBody.Data[0].ID)
+}
+
+func TestCreateTicketPlanningFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	estimate := -1
+	reqBody := models.TicketCreateRequest{
+		Title:    "Badly Planned Ticket",
+		Priority: "critical",
+		Estimate: &estimate,
+	}
+
+	ticketJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	// validation key is ordered alphabetically
+	// last validation ends with a dot
+	assert.Len(t, responseBody.Message, 2)
+	assert.Equal(t, "estimate: must be at least 0", responseBody.Message[0])
+	assert.Equal(t, "priority: only allows \"low\", \"medium\", \"high\", or \"urgent\".", responseBody.Message[1])
+}
+
+func TestGetTicketListSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// first page
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets?limit=1&sort_by=created_at", nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var firstPage models.TicketListResponse
+	err = json.Unmarshal(body, &firstPage)
+	assert.Nil(t, err)
+
+	assert.Len(t, firstPage.Data, 1)
+	assert.NotEmpty(t, firstPage.NextCursor)
+
+	// second page
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets?limit=1&sort_by=created_at&cursor="+firstPage.NextCursor, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var secondPage models.TicketListResponse
+	err = json.Unmarshal(body, &secondPage)
+	assert.Nil(t, err)
+
+	assert.Len(t, secondPage.Data, 1)
+	assert.NotEqual(t, firstPage.Data[0].ID, secondPage.Data[0].ID)
+
+	// no tickets found is not an error
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets?title=nosuchticket", nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var emptyResponseBody models.TicketListResponse
+	err = json.Unmarshal(body, &emptyResponseBody)
+	assert.Nil(t, err)
+
+	assert.Len(t, emptyResponseBody.Data, 0)
+	assert.Empty(t, emptyResponseBody.NextCursor)
+}
+
+func TestGetTicketListFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets?sort_by=color&limit=1000", nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	// validation key is ordered alphabetically
+	// last validation ends with a dot
+	assert.Len(t, responseBody.Message, 2)
+	assert.Equal(t, "limit: must be at most 100", responseBody.Message[0])
+	assert.Equal(t, "sort_by: only allows \"rank\", \"created_at\", \"updated_at\", or \"title\".", responseBody.Message[1])
+}
+
+func TestGetTicketListByQuerySuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	getTicketIds := func(query string) []string {
+		request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets?q="+url.QueryEscape(query), nil, token.AccessToken)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response := recorder.Result()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		body, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+
+		var responseBody models.TicketListResponse
+		err = json.Unmarshal(body, &responseBody)
+		assert.Nil(t, err)
+
+		ticketIds := []string{}
+		for _, ticket := range responseBody.Data {
+			ticketIds = append(ticketIds, ticket.ID)
+		}
+		return ticketIds
+	}
+
+	assert.Contains(t, getTicketIds(`status:todo,doing assignee:backend -label:wontfix updated>-1d "ticket description"`), testhelper.TEST_TICKET.ID)
+	assert.NotContains(t, getTicketIds(`-assignee:backend`), testhelper.TEST_TICKET.ID)
+	assert.NotContains(t, getTicketIds(`created<2000-01-01`), testhelper.TEST_TICKET.ID)
+
+	// "me" is the user, as in ?assignee=me
+	assert.NotContains(t, getTicketIds(`assignee:me`), testhelper.TEST_TICKET.ID)
+	assert.Contains(t, getTicketIds(`assignee:me,backend`), testhelper.TEST_TICKET.ID)
+
+	// wildcards in titles are matched literally
+	assert.Contains(t, getTicketIds(`title:"test ticket"`), testhelper.TEST_TICKET.ID)
+	assert.NotContains(t, getTicketIds(`title:test%ticket`), testhelper.TEST_TICKET.ID)
+	assert.NotContains(t, getTicketIds(`title:test_ticket`), testhelper.TEST_TICKET.ID)
+}
+
+func TestGetTicketListByQueryFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets?q="+url.QueryEscape("color:red"), nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"q: unknown field \"color\"."}, responseBody.Message)
+}
+
 func TestUpdateTicketSuccess(t *testing.T) {
 	router := routes.GetRoutes(D)
 
@@ -271,6 +529,315 @@ func TestUpdateTicketFailed(t *testing.T) {
 	assert.Equal(t, "title: must have length between 8 and 50.", responseBody.Message[2])
 }
 
+func TestPatchTicketSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// get the current version of the ticket
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID, nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var currentTicket models.TicketResponse
+	err = json.Unmarshal(body, &currentTicket)
+	assert.Nil(t, err)
+
+	etag := response.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// only update the description
+	request = testhelper.GetHTTPRequest(http.MethodPatch, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID, strings.NewReader(`{"description":"Patched Description"}`), token.AccessToken)
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("If-Match", etag)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TicketResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Patched Description", responseBody.Description)
+	assert.Equal(t, currentTicket.Title, responseBody.Title)
+	assert.Equal(t, currentTicket.Status, responseBody.Status)
+	assert.Equal(t, currentTicket.Assignees, responseBody.Assignees)
+	assert.Equal(t, currentTicket.Version+1, responseBody.Version)
+	assert.NotEqual(t, etag, response.Header.Get("ETag"))
+
+	// null clears a field
+	request = testhelper.GetHTTPRequest(http.MethodPatch, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID, strings.NewReader(`{"assignees":null}`), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var clearedResponseBody models.TicketResponse
+	err = json.Unmarshal(body, &clearedResponseBody)
+	assert.Nil(t, err)
+
+	assert.Len(t, clearedResponseBody.Assignees, 0)
+	assert.Equal(t, "Patched Description", clearedResponseBody.Description)
+}
+
+func TestPatchTicketFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// failed because of a stale version
+	request := testhelper.GetHTTPRequest(http.MethodPatch, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID, strings.NewReader(`{"title":"Stale Ticket Title"}`), token.AccessToken)
+	request.Header.Set("If-Match", `"1"`)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "ticket has been modified since it was fetched", responseBody.Message)
+
+	// failed because of validation
+	request = testhelper.GetHTTPRequest(http.MethodPatch, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID, strings.NewReader(`{"title":null}`), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var validationResponseBody models.ValidationErrorMessage
+	err = json.Unmarshal(body, &validationResponseBody)
+	assert.Nil(t, err)
+
+	// last validation ends with a dot
+	assert.Len(t, validationResponseBody.Message, 1)
+	assert.Equal(t, "title: is required.", validationResponseBody.Message[0])
+}
+
+func TestMoveTicketSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	tokenData, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// create a ticket in the done column
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:  "Ticket that is already done",
+		Status: "done",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+testhelper.TEST_BOARD.ID+"/tickets", strings.NewReader(string(ticketJson)), tokenData.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var doneTicket models.TicketResponse
+	err = json.Unmarshal(body, &doneTicket)
+	assert.Nil(t, err)
+
+	// move the test ticket right above it
+	reqBody := models.TicketMoveRequest{
+		Status:  "done",
+		AfterID: doneTicket.ID,
+	}
+
+	moveJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/move", strings.NewReader(string(moveJson)), tokenData.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TicketResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, reqBody.Status, responseBody.Status)
+	assert.Less(t, responseBody.Rank, doneTicket.Rank)
+}
+
+func TestMoveTicketFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	tokenData, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// the neighbour doesn't exist in the target column
+	reqBody := models.TicketMoveRequest{
+		Status:   "todo",
+		BeforeID: "wrongticketid",
+	}
+
+	moveJson, err := json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/move", strings.NewReader(string(moveJson)), tokenData.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "before_id and after_id must be tickets of the target column in the right order", responseBody.Message)
+}
+
+func TestBatchTicketsSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	tokenData, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// failed operations don't stop the other operations
+	requestBody := `{"operations":[
+		{"op":"create","ticket":{"title":"Batch Ticket","description":"Batch Description"}},
+		{"op":"update","ticket_id":"` + testhelper.TEST_TICKET.ID + `","patch":{"description":"Batch Patched Description"}},
+		{"op":"delete","ticket_id":"wrongticketid"}
+	]}`
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets:batch", strings.NewReader(requestBody), tokenData.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TicketBatchResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Len(t, responseBody.Results, 3)
+	assert.Equal(t, http.StatusCreated, responseBody.Results[0].Status)
+	assert.Equal(t, "Batch Ticket", responseBody.Results[0].Ticket.Title)
+	assert.Equal(t, http.StatusOK, responseBody.Results[1].Status)
+	assert.Equal(t, "Batch Patched Description", responseBody.Results[1].Ticket.Description)
+	assert.Equal(t, http.StatusNotFound, responseBody.Results[2].Status)
+	assert.Nil(t, responseBody.Results[2].Ticket)
+
+	createdTicketId := responseBody.Results[0].Ticket.ID
+
+	// nothing is applied when one operation fails
+	requestBody = `{"all_or_nothing":true,"operations":[
+		{"op":"delete","ticket_id":"` + createdTicketId + `"},
+		{"op":"update","ticket_id":"wrongticketid","patch":{"title":"Wrong Ticket"}}
+	]}`
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets:batch", strings.NewReader(requestBody), tokenData.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var failedResponseBody models.TicketBatchResponse
+	err = json.Unmarshal(body, &failedResponseBody)
+	assert.Nil(t, err)
+
+	assert.Len(t, failedResponseBody.Results, 2)
+	assert.Equal(t, http.StatusFailedDependency, failedResponseBody.Results[0].Status)
+	assert.Equal(t, http.StatusNotFound, failedResponseBody.Results[1].Status)
+
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+createdTicketId, nil, tokenData.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// clean up through the alias of the route
+	requestBody = `{"operations":[{"op":"delete","ticket_id":"` + createdTicketId + `"}]}`
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/batch", strings.NewReader(requestBody), tokenData.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestBatchTicketsFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	tokenData, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets:batch", strings.NewReader(`{"operations":[{"op":"archive","ticket_id":"`+testhelper.TEST_TICKET.ID+`"}]}`), tokenData.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// unknown actions don't exist
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets:archive", strings.NewReader(`{"operations":[]}`), tokenData.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
 func TestDeleteTicketSuccess(t *testing.T) {
 	router := routes.GetRoutes(D)
 
@@ -336,3 +903,124 @@ func TestDeleteTicketFailed(t *testing.T) {
 
 	assert.Equal(t, "ticket not found", responseBody.Message)
 }
+
+func TestRestoreTicketSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	tokenData, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// the deleted ticket is in the trash
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/trash", nil, tokenData.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var trashResponseBody []models.TicketResponse
+	err = json.Unmarshal(body, &trashResponseBody)
+	assert.Nil(t, err)
+
+	assert.Len(t, trashResponseBody, 1)
+	assert.Equal(t, testhelper.TEST_TICKET.ID, trashResponseBody[0].ID)
+	assert.NotNil(t, trashResponseBody[0].DeletedAt)
+
+	// restore the ticket
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/restore", nil, tokenData.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TicketResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, testhelper.TEST_TICKET.ID, responseBody.ID)
+	assert.Nil(t, responseBody.DeletedAt)
+
+	// the ticket can be found again
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID, nil, tokenData.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestPurgeTicketSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	tokenData, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// only tickets in the trash can be purged
+	request := testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID, nil, tokenData.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	request = testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/purge", nil, tokenData.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.TicketDeleteResponse
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "success", responseBody.Message)
+
+	// the ticket can't be restored anymore
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+testhelper.TEST_TICKET.ID+"/restore", nil, tokenData.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestPurgeTicketFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	tokenData, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/tickets/wrongticketid/purge", nil, tokenData.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var responseBody models.ErrorMessage
+	err = json.Unmarshal(body, &responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "ticket not found in trash", responseBody.Message)
+}
@@ -45,6 +45,9 @@ func TestCreateUserSuccess(t *testing.T) {
 	assert.Equal(t, reqBody.Email, responseBody.Email)
 	assert.Equal(t, reqBody.FirstName, responseBody.FirstName)
 	assert.Equal(t, reqBody.LastName, responseBody.LastName)
+
+	// the username is derived from the email when left out
+	assert.Equal(t, "newuser", responseBody.Username)
 }
 
 func TestCreateUserFailed(t *testing.T) {
@@ -111,4 +114,33 @@ func TestCreateUserFailed(t *testing.T) {
 	assert.Nil(t, err)
 
 	assert.Equal(t, "email is already used", emailResonseBody.Message)
+
+	// failed because the username is already used
+	reqBody = models.UserCreateRequest{
+		FirstName: "Test",
+		LastName:  "Test",
+		Email:     "another-testuser@example.com",
+		Username:  "testuser",
+		Password:  "CorrectlyF0rmattedP@ssword",
+	}
+
+	createUserJson, err = json.Marshal(reqBody)
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/iam/v1/users", strings.NewReader(string(createUserJson)), "")
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var usernameResponseBody models.ErrorMessage
+	err = json.Unmarshal(body, &usernameResponseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "username is already used", usernameResponseBody.Message)
 }
@@ -0,0 +1,99 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebSocketSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	member, memberToken := createWorkspaceTestUser(t, router, "ws-member@example.com")
+	_, outsiderToken := createWorkspaceTestUser(t, router, "ws-outsider@example.com")
+	board := createAssigneeBoard(t, router, token.AccessToken, member)
+
+	memberConn := dialTestWebSocket(t, server, memberToken)
+	defer memberConn.Close()
+
+	outsiderConn := dialTestWebSocket(t, server, outsiderToken)
+	defer outsiderConn.Close()
+
+	// the clients are registered right after the handshake
+	time.Sleep(100 * time.Millisecond)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{Title: "Broadcast ticket"})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	// members of the workspace get the event
+	var message models.WSMessage
+	memberConn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	err = memberConn.ReadJSON(&message)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "created", message.Event)
+	if assert.NotNil(t, message.Ticket) {
+		assert.Equal(t, "Broadcast ticket", message.Ticket.Title)
+	}
+
+	// users outside of the workspace don't
+	outsiderConn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	_, _, err = outsiderConn.ReadMessage()
+	assert.NotNil(t, err)
+}
+
+func TestWebSocketFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// clients without an access token can't connect
+	conn, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if conn != nil {
+		conn.Close()
+	}
+	assert.NotNil(t, err)
+	if assert.NotNil(t, response) {
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+}
+
+// helpers
+func dialTestWebSocket(t *testing.T, server *httptest.Server, accessToken string) *websocket.Conn {
+	header := http.Header{}
+	header.Add("Authorization", "Bearer "+accessToken)
+	// the websocket only accepts connections from the frontend
+	if baseUrl := os.Getenv("BASE_URL"); baseUrl != "" {
+		header.Add("Origin", baseUrl)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", header)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	return conn
+}
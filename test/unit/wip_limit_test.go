@@ -0,0 +1,195 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWIPLimitSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	board := createWIPLimitBoard(t, router, token.AccessToken)
+
+	// the first ticket fits into the column
+	createWIPLimitTicket(t, router, token.AccessToken, board.ID, "", http.StatusCreated)
+
+	// admins of the workspace can exceed the limit
+	createWIPLimitTicket(t, router, token.AccessToken, board.ID, "?override_wip_limit=true", http.StatusCreated)
+
+	// the limit and the count are returned with the tickets of the board
+	request := testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/boards/"+board.ID+"/tickets", nil, token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticketList models.TicketListResponse
+	err = json.Unmarshal(body, &ticketList)
+	assert.Nil(t, err)
+
+	assert.Len(t, ticketList.Columns, 3)
+	for _, column := range ticketList.Columns {
+		if column.Key != "doing" {
+			assert.Nil(t, column.WIPLimit)
+			continue
+		}
+
+		assert.NotNil(t, column.WIPLimit)
+		assert.Equal(t, 1, *column.WIPLimit)
+		assert.Equal(t, int64(2), column.Count)
+	}
+}
+
+func TestWIPLimitFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	board := createWIPLimitBoard(t, router, token.AccessToken)
+
+	createWIPLimitTicket(t, router, token.AccessToken, board.ID, "", http.StatusCreated)
+
+	// the column is full
+	body := createWIPLimitTicket(t, router, token.AccessToken, board.ID, "", http.StatusConflict)
+
+	var errorMessage models.WIPLimitErrorMessage
+	err = json.Unmarshal(body, &errorMessage)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "doing", errorMessage.Status)
+	assert.Equal(t, 1, errorMessage.WIPLimit)
+	assert.Equal(t, int64(1), errorMessage.Count)
+
+	// tickets can't be moved into the full column either
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title: "Ticket waiting in todo",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	moveJson, err := json.Marshal(models.TicketMoveRequest{Status: "doing"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/tickets/"+ticket.ID+"/move", strings.NewReader(string(moveJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+
+	// a WIP limit below 1 is invalid
+	zero := 0
+	columnsJson, err := json.Marshal(models.BoardColumnsUpdateRequest{
+		Columns: []models.BoardColumnRequest{
+			{Key: "todo", Name: "To Do", Color: "#94a3b8", WIPLimit: &zero},
+		},
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/boards/"+board.ID+"/columns", strings.NewReader(string(columnsJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+// helpers
+
+// createWIPLimitBoard creates a board whose doing column has a WIP limit of 1
+func createWIPLimitBoard(t *testing.T, router *gin.Engine, accessToken string) models.BoardResponse {
+	boardJson, err := json.Marshal(models.BoardCreateRequest{Name: "WIP Board"})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), accessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var board models.BoardResponse
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	wipLimit := 1
+	columnsJson, err := json.Marshal(models.BoardColumnsUpdateRequest{
+		Columns: []models.BoardColumnRequest{
+			{Key: "todo", Name: "To Do", Color: "#94a3b8"},
+			{Key: "doing", Name: "Doing", Color: "#3b82f6", WIPLimit: &wipLimit},
+			{Key: "done", Name: "Done", Color: "#22c55e"},
+		},
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/boards/"+board.ID+"/columns", strings.NewReader(string(columnsJson)), accessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	return board
+}
+
+// createWIPLimitTicket creates a ticket in the doing column and returns the response body
+func createWIPLimitTicket(t *testing.T, router *gin.Engine, accessToken string, boardId string, query string, expectedStatus int) []byte {
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{
+		Title:  "Ticket in progress",
+		Status: "doing",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+boardId+"/tickets"+query, strings.NewReader(string(ticketJson)), accessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, expectedStatus, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	return body
+}
@@ -0,0 +1,384 @@
+package unit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	testhelper "github.com/Manuel-Leleuly/kanban-flow-go/helpers/test"
+	"github.com/Manuel-Leleuly/kanban-flow-go/models"
+	"github.com/Manuel-Leleuly/kanban-flow-go/routes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	teammate, teammateToken := createWorkspaceTestUser(t, router, "teammate@example.com")
+
+	workspace := createTestWorkspace(t, router, token.AccessToken)
+	assert.Equal(t, models.WORKSPACE_ROLE_OWNER, workspace.Role)
+
+	// boards of the workspace are shared with its members
+	boardJson, err := json.Marshal(models.BoardCreateRequest{Name: "Shared Board"})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var board models.BoardResponse
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	assert.Equal(t, workspace.ID, board.WorkspaceID)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{Title: "Shared ticket"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	// the teammate joins as a viewer
+	memberJson, err := json.Marshal(models.MembershipCreateRequest{
+		Email: teammate.Email,
+		Role:  models.WORKSPACE_ROLE_VIEWER,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/members", strings.NewReader(string(memberJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	// viewers can read the tickets but not change them
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+ticket.ID, nil, teammateToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	patchJson := `{"title": "Renamed shared ticket"}`
+	request = testhelper.GetHTTPRequest(http.MethodPatch, "/kanban/v1/tickets/"+ticket.ID, strings.NewReader(patchJson), teammateToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	// members can
+	roleJson, err := json.Marshal(models.MembershipUpdateRequest{Role: models.WORKSPACE_ROLE_MEMBER})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/workspaces/"+workspace.ID+"/members/"+teammate.ID, strings.NewReader(string(roleJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	request = testhelper.GetHTTPRequest(http.MethodPatch, "/kanban/v1/tickets/"+ticket.ID, strings.NewReader(patchJson), teammateToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// the shared board is listed next to the boards of the personal workspace
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/boards", nil, teammateToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var boards []models.BoardResponse
+	err = json.Unmarshal(body, &boards)
+	assert.Nil(t, err)
+
+	boardIds := []string{}
+	for _, board := range boards {
+		boardIds = append(boardIds, board.ID)
+	}
+	assert.Contains(t, boardIds, board.ID)
+	assert.NotContains(t, boardIds, testhelper.TEST_BOARD.ID)
+}
+
+func TestWorkspaceFailed(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	outsider, outsiderToken := createWorkspaceTestUser(t, router, "outsider@example.com")
+	workspace := createTestWorkspace(t, router, token.AccessToken)
+
+	boardJson, err := json.Marshal(models.BoardCreateRequest{Name: "Private Board"})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/boards", strings.NewReader(string(boardJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var board models.BoardResponse
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	ticketJson, err := json.Marshal(models.TicketCreateRequest{Title: "Private ticket"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards/"+board.ID+"/tickets", strings.NewReader(string(ticketJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var ticket models.TicketResponse
+	err = json.Unmarshal(body, &ticket)
+	assert.Nil(t, err)
+
+	// tickets of other workspaces are not found, by their ID or by their key
+	for _, ticketId := range []string{ticket.ID, ticket.Key} {
+		request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+ticketId, nil, outsiderToken)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response = recorder.Result()
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	}
+
+	// while the members of its workspace can see it
+	request = testhelper.GetHTTPRequest(http.MethodGet, "/kanban/v1/tickets/"+ticket.ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	memberJson, err := json.Marshal(models.MembershipCreateRequest{
+		Email: outsider.Email,
+		Role:  models.WORKSPACE_ROLE_MEMBER,
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/members", strings.NewReader(string(memberJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	// members can't manage the workspace
+	workspaceJson, err := json.Marshal(models.WorkspaceRequest{Name: "Taken Over Workspace"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/workspaces/"+workspace.ID, strings.NewReader(string(workspaceJson)), outsiderToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	// users can only be added once
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/members", strings.NewReader(string(memberJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// the last owner can't leave
+	request = testhelper.GetHTTPRequest(http.MethodDelete, "/kanban/v1/workspaces/"+workspace.ID+"/members/"+testhelper.TEST_USER.ID, nil, token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// unknown roles are invalid
+	roleJson, err := json.Marshal(models.MembershipUpdateRequest{Role: "superuser"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPut, "/kanban/v1/workspaces/"+workspace.ID+"/members/"+outsider.ID, strings.NewReader(string(roleJson)), token.AccessToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestPersonalWorkspaceSuccess(t *testing.T) {
+	router := routes.GetRoutes(D)
+
+	token, err := testhelper.GetTestToken(D)
+	assert.Nil(t, err)
+
+	// the shared workspace is older than the personal workspace of the new user
+	workspace := createTestWorkspace(t, router, token.AccessToken)
+	owner, ownerToken := createWorkspaceTestUser(t, router, "personal-owner@example.com")
+
+	memberJson, err := json.Marshal(models.MembershipCreateRequest{
+		Email: owner.Email,
+		Role:  models.WORKSPACE_ROLE_OWNER,
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces/"+workspace.ID+"/members", strings.NewReader(string(memberJson)), token.AccessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusCreated, recorder.Result().StatusCode)
+
+	// owning an older workspace doesn't change the personal workspace
+	boardJson, err := json.Marshal(models.BoardCreateRequest{Name: "Personal Board"})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/boards", strings.NewReader(string(boardJson)), ownerToken)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var board models.BoardResponse
+	err = json.Unmarshal(body, &board)
+	assert.Nil(t, err)
+
+	assert.NotEmpty(t, board.WorkspaceID)
+	assert.NotEqual(t, workspace.ID, board.WorkspaceID)
+}
+
+// helpers
+
+// createWorkspaceTestUser registers a user and returns it together with its access token
+func createWorkspaceTestUser(t *testing.T, router *gin.Engine, email string) (models.UserResponse, string) {
+	userJson, err := json.Marshal(models.UserCreateRequest{
+		Email:     email,
+		FirstName: "Team",
+		LastName:  "Mate",
+		Password:  "teamMate@123",
+	})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/iam/v1/users", strings.NewReader(string(userJson)), "")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var user models.UserResponse
+	err = json.Unmarshal(body, &user)
+	assert.Nil(t, err)
+
+	loginJson, err := json.Marshal(models.Login{
+		Email:    email,
+		Password: "teamMate@123",
+	})
+	assert.Nil(t, err)
+
+	request = testhelper.GetHTTPRequest(http.MethodPost, "/iam/v1/login", strings.NewReader(string(loginJson)), "")
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var token models.Token
+	err = json.Unmarshal(body, &token)
+	assert.Nil(t, err)
+
+	return user, token.AccessToken
+}
+
+func createTestWorkspace(t *testing.T, router *gin.Engine, accessToken string) models.WorkspaceResponse {
+	workspaceJson, err := json.Marshal(models.WorkspaceRequest{Name: "Shared Workspace"})
+	assert.Nil(t, err)
+
+	request := testhelper.GetHTTPRequest(http.MethodPost, "/kanban/v1/workspaces", strings.NewReader(string(workspaceJson)), accessToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	var workspace models.WorkspaceResponse
+	err = json.Unmarshal(body, &workspace)
+	assert.Nil(t, err)
+
+	return workspace
+}